@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"github.com/S1nceU/CRMS/apps/api/model"
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 	"log"
@@ -18,12 +19,122 @@ type DatabaseConfig struct {
 	Database string `mapstructure:"DATABASE"`
 }
 
+type RetentionConfig struct {
+	Enabled       bool `mapstructure:"ENABLED"`
+	IntervalHours int  `mapstructure:"INTERVAL_HOURS"`
+}
+
+type BillingConfig struct {
+	Currency string      `mapstructure:"CURRENCY"` // Currency of rate plans and of stays entered without one
+	Taxes    []TaxConfig `mapstructure:"TAXES"`
+}
+
+type TaxConfig struct {
+	Name      string  `mapstructure:"NAME"`
+	Rate      float64 `mapstructure:"RATE"`      // Percent
+	Inclusive bool    `mapstructure:"INCLUSIVE"` // Already included in the charges
+}
+
+type PropertyConfig struct {
+	Name     string `mapstructure:"NAME"`
+	Address  string `mapstructure:"ADDRESS"`
+	Phone    string `mapstructure:"PHONE"`
+	Email    string `mapstructure:"EMAIL"`
+	TaxId    string `mapstructure:"TAX_ID"`
+	FontPath string `mapstructure:"FONT_PATH"` // TrueType font used in PDFs, needed to print non-Latin names
+}
+
+type RegistrationConfig struct {
+	Domestic string                    `mapstructure:"DOMESTIC"` // Alpha3 of the citizens not reported to the authorities
+	Format   string                    `mapstructure:"FORMAT"`   // csv or fixed
+	Fields   []RegistrationFieldConfig `mapstructure:"FIELDS"`
+}
+
+type RegistrationFieldConfig struct {
+	Field string `mapstructure:"FIELD"` // Name, PassportNumber, Nationality, Nation, Birthday, Gender, CheckIn, CheckOut or Room
+	Label string `mapstructure:"LABEL"`
+	Width int    `mapstructure:"WIDTH"` // Needed by the fixed format
+}
+
 type Config struct {
-	Mode            string `mapstructure:"MODE"`
-	Port            int    `mapstructure:"PORT"`
-	FrontendOrigin  string `mapstructure:"FRONTEND_ORIGIN"`
-	CookieSecure    bool   `mapstructure:"COOKIE_SECURE"`
-	*DatabaseConfig `mapstructure:"DATABASE"`
+	Mode                string `mapstructure:"MODE"`
+	Port                int    `mapstructure:"PORT"`
+	FrontendOrigin      string `mapstructure:"FRONTEND_ORIGIN"`
+	CookieSecure        bool   `mapstructure:"COOKIE_SECURE"`
+	*DatabaseConfig     `mapstructure:"DATABASE"`
+	*RetentionConfig    `mapstructure:"RETENTION"`
+	*BillingConfig      `mapstructure:"BILLING"`
+	*PropertyConfig     `mapstructure:"PROPERTY"`
+	*RegistrationConfig `mapstructure:"REGISTRATION"`
+}
+
+// defaultRegistrationFields is the layout used when none is configured
+var defaultRegistrationFields = []model.RegistrationField{
+	{Field: "Name", Label: "Name", Width: 40},
+	{Field: "PassportNumber", Label: "Passport Number", Width: 20},
+	{Field: "Nationality", Label: "Nationality", Width: 3},
+	{Field: "Birthday", Label: "Birthday", Width: 10},
+	{Field: "Gender", Label: "Gender", Width: 6},
+	{Field: "CheckIn", Label: "Check-in", Width: 10},
+	{Field: "CheckOut", Label: "Check-out", Width: 10},
+	{Field: "Room", Label: "Room", Width: 6},
+}
+
+// Currency returns the billing currency, TWD when none is configured
+func Currency() string {
+	if Val.BillingConfig == nil || Val.BillingConfig.Currency == "" {
+		return "TWD"
+	}
+	return Val.BillingConfig.Currency
+}
+
+// TaxRates returns the configured taxes applied to every stay
+func TaxRates() []model.TaxRate {
+	var rates []model.TaxRate
+	if Val.BillingConfig == nil {
+		return rates
+	}
+	for _, tax := range Val.BillingConfig.Taxes {
+		rates = append(rates, model.TaxRate{
+			Name:      tax.Name,
+			Rate:      tax.Rate,
+			Inclusive: tax.Inclusive,
+		})
+	}
+	return rates
+}
+
+// DomesticCitizenship returns the Alpha3 of the guests not to report to the authorities, TWN when none is configured
+func DomesticCitizenship() string {
+	if Val.RegistrationConfig == nil || Val.RegistrationConfig.Domestic == "" {
+		return "TWN"
+	}
+	return Val.RegistrationConfig.Domestic
+}
+
+// RegistrationLayout returns the layout of the foreign-guest registration file, CSV with every field by default
+func RegistrationLayout() model.RegistrationLayout {
+	layout := model.RegistrationLayout{
+		Format: model.RegistrationFormatCSV,
+		Fields: defaultRegistrationFields,
+	}
+	if Val.RegistrationConfig == nil {
+		return layout
+	}
+	if Val.RegistrationConfig.Format != "" {
+		layout.Format = Val.RegistrationConfig.Format
+	}
+	if len(Val.RegistrationConfig.Fields) != 0 {
+		layout.Fields = nil
+		for _, field := range Val.RegistrationConfig.Fields {
+			layout.Fields = append(layout.Fields, model.RegistrationField{
+				Field: field.Field,
+				Label: field.Label,
+				Width: field.Width,
+			})
+		}
+	}
+	return layout
 }
 
 // Init is a function to read config.yaml
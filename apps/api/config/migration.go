@@ -0,0 +1,158 @@
+package config
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+// MigrateCustomerCreatedAt gives the customers recorded before CreatedAt existed the time of the migration, so the
+// retention of those without stays or reservations counts from then. It runs before the column is made not null.
+func MigrateCustomerCreatedAt(db *gorm.DB) error {
+	if !db.Migrator().HasTable("customers") {
+		return nil
+	}
+	if !db.Migrator().HasColumn("customers", "CreatedAt") {
+		if err := db.Exec("ALTER TABLE customers ADD COLUMN CreatedAt datetime(3) NULL").Error; err != nil {
+			return err
+		}
+	}
+	result := db.Exec("UPDATE customers SET CreatedAt = ? WHERE CreatedAt IS NULL", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 0 {
+		log.Println("Migrate customer creation dates successfully")
+	}
+	return nil
+}
+
+// MigrateHistoryStayDates converts the single Date of existing histories into one-night stays with CheckIn and CheckOut.
+// MySQL commits every DDL statement on its own, so each step checks whether it has already run.
+func MigrateHistoryStayDates(db *gorm.DB) error {
+	if !db.Migrator().HasTable("histories") || !db.Migrator().HasColumn("histories", "Date") {
+		return nil
+	}
+	if !db.Migrator().HasColumn("histories", "CheckIn") {
+		if err := db.Exec("ALTER TABLE histories ADD COLUMN CheckIn datetime(3) NULL, ADD COLUMN CheckOut datetime(3) NULL").Error; err != nil {
+			return err
+		}
+	}
+	if err := db.Exec("UPDATE histories SET CheckIn = Date, CheckOut = DATE_ADD(Date, INTERVAL 1 DAY) WHERE CheckIn IS NULL").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("ALTER TABLE histories DROP COLUMN Date").Error; err != nil {
+		return err
+	}
+	log.Println("Migrate history dates to stays successfully")
+	return nil
+}
+
+// MigrateHistoryRooms turns the free-text Room of existing histories into Room records referenced by RoomId.
+// Spellings of the same number share one room whose capacity is the largest party ever recorded in it.
+// MySQL commits every DDL statement on its own, so the column is dropped once the rooms are committed and each step
+// checks whether it has already run.
+func MigrateHistoryRooms(db *gorm.DB) error {
+	if !db.Migrator().HasTable("histories") || !db.Migrator().HasColumn("histories", "Room") {
+		return nil
+	}
+	if !db.Migrator().HasColumn("histories", "RoomId") {
+		if err := db.Exec("ALTER TABLE histories ADD COLUMN RoomId char(36) NULL").Error; err != nil {
+			return err
+		}
+	}
+
+	var usages []struct {
+		Room     string
+		Capacity int
+	}
+	if err := db.Table("histories").Select("Room, MAX(NumberOfPeople) AS Capacity").Where("RoomId IS NULL").Group("Room").Scan(&usages).Error; err != nil {
+		return err
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, usage := range usages {
+			number := model.NormalizeRoomNumber(usage.Room)
+			room := &model.Room{}
+			if number == "" {
+				number = "UNASSIGNED"
+			}
+			if err := tx.Where("Number = ?", number).Find(room).Error; err != nil {
+				return err
+			}
+			if room.Id == uuid.Nil {
+				room = &model.Room{
+					Id:       uuid.New(),
+					Number:   number,
+					Type:     "Standard",
+					Capacity: usage.Capacity,
+					Active:   number != "UNASSIGNED",
+				}
+				if err := tx.Create(room).Error; err != nil {
+					return err
+				}
+			} else if room.Capacity < usage.Capacity {
+				if err := tx.Model(room).Update("Capacity", usage.Capacity).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec("UPDATE histories SET RoomId = ? WHERE Room = ? AND RoomId IS NULL", room.Id, usage.Room).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = db.Exec("ALTER TABLE histories DROP COLUMN Room").Error; err != nil {
+		return err
+	}
+	log.Println("Migrate history rooms successfully")
+	return nil
+}
+
+// MigrateHistoryMoney gives the histories recorded before the price breakdown the billing currency, taking their
+// Price as the room charge. Their taxes were never recorded, so they get no tax lines.
+func MigrateHistoryMoney(db *gorm.DB) error {
+	currency := Currency()
+	unit := model.MoneyFromMajor(1, currency).Amount
+	result := db.Exec("UPDATE histories SET Currency = ?, RoomCharge = Price * ?, TotalAmount = Price * ? WHERE Currency = ''",
+		currency, unit, unit)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 0 {
+		log.Println("Migrate history prices to money successfully")
+	}
+	return nil
+}
+
+// MigrateStayGuests registers the customer of every stay without guests as its primary guest
+func MigrateStayGuests(db *gorm.DB) error {
+	result := db.Exec(`INSERT INTO stay_guests (Id, HistoryId, CustomerId, IsPrimary)
+		SELECT UUID(), h.Id, h.CustomerId, TRUE FROM histories h
+		WHERE h.Kind = ? AND NOT EXISTS (SELECT 1 FROM stay_guests g WHERE g.HistoryId = h.Id)`, model.HistoryKindStay)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 0 {
+		log.Println("Migrate history customers to stay guests successfully")
+	}
+	return nil
+}
+
+// MigrateStaySegments gives every stay without segments one segment in its room covering the whole stay
+func MigrateStaySegments(db *gorm.DB) error {
+	result := db.Exec(`INSERT INTO stay_segments (Id, HistoryId, RoomId, CheckIn, CheckOut)
+		SELECT UUID(), h.Id, h.RoomId, h.CheckIn, h.CheckOut FROM histories h
+		WHERE h.Kind = ? AND NOT EXISTS (SELECT 1 FROM stay_segments s WHERE s.HistoryId = h.Id)`, model.HistoryKindStay)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 0 {
+		log.Println("Migrate history rooms to stay segments successfully")
+	}
+	return nil
+}
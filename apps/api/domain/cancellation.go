@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+)
+
+// CancellationPolicyRepository is an interface for cancellation policy repository
+type CancellationPolicyRepository interface {
+	ListCancellationPolicies() ([]*model.CancellationPolicy, error)                                  // Get all CancellationPolicies
+	GetCancellationPolicyById(policy *model.CancellationPolicy) (*model.CancellationPolicy, error)   // Get CancellationPolicy by PolicyId
+	GetCancellationPolicyByName(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) // Get CancellationPolicy by Name
+	CreateCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error)    // Create a new CancellationPolicy
+	UpdateCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error)    // Update CancellationPolicy data
+	DeleteCancellationPolicy(policy *model.CancellationPolicy) error                                 // Delete CancellationPolicy by PolicyId
+	CountReservationsByCancellationPolicy(policy *model.CancellationPolicy) (int64, error)           // Count Reservations booked under CancellationPolicy
+}
+
+// CancellationPolicyService is an interface for cancellation policy service
+type CancellationPolicyService interface {
+	ListCancellationPolicies() ([]*model.CancellationPolicy, error)                               // Get all CancellationPolicies
+	GetCancellationPolicyById(policyId uuid.UUID) (*model.CancellationPolicy, error)              // Get CancellationPolicy by PolicyId
+	CreateCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) // Create a new CancellationPolicy
+	UpdateCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) // Update CancellationPolicy data
+	DeleteCancellationPolicy(policyId uuid.UUID) error                                            // Delete CancellationPolicy by PolicyId
+}
@@ -2,31 +2,51 @@ package domain
 
 import (
 	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
 	"github.com/google/uuid"
 )
 
 // CustomerRepository is an interface for customer repository
 type CustomerRepository interface {
-	ListCustomers() ([]*model.Customer, error)                                      // Get all Customers
-	ListCustomersByCitizenship(customer *model.Customer) ([]*model.Customer, error) // Get all Customers by Citizenship
-	ListCustomersByName(customer *model.Customer) ([]*model.Customer, error)        // Get Customer by CustomerName
-	ListCustomersByPhone(customer *model.Customer) ([]*model.Customer, error)       // Get Customer by CustomerPhone
-	GetCustomerByNationalId(customer *model.Customer) (*model.Customer, error)      // Get Customer by ID
-	GetCustomerByCustomerId(customer *model.Customer) (*model.Customer, error)      // Get Customer by CustomerId
-	CreateCustomer(customer *model.Customer) (*model.Customer, error)               // Create a new Customer
-	UpdateCustomer(customer *model.Customer) (*model.Customer, error)               // Update Customer data
-	DeleteCustomer(customer *model.Customer) error                                  // Delete Customer by CustomerId
+	ListCustomers() ([]*model.Customer, error)                                                               // Get all Customers
+	ListCustomersByCitizenship(customer *model.Customer) ([]*model.Customer, error)                          // Get all Customers by Citizenship
+	ListCustomersByName(customer *model.Customer) ([]*model.Customer, error)                                 // Get Customer by CustomerName
+	ListCustomersByPhone(customer *model.Customer) ([]*model.Customer, error)                                // Get Customer by CustomerPhone
+	GetCustomerByNationalId(customer *model.Customer) (*model.Customer, error)                               // Get Customer by ID
+	GetCustomerByCustomerId(customer *model.Customer) (*model.Customer, error)                               // Get Customer by CustomerId
+	CreateCustomer(customer *model.Customer) (*model.Customer, error)                                        // Create a new Customer
+	UpdateCustomer(customer *model.Customer) (*model.Customer, error)                                        // Update Customer data
+	DeleteCustomer(customer *model.Customer) error                                                           // Delete Customer by CustomerId with their Histories and Reservations
+	CountPaymentsByCustomer(customer *model.Customer) (int64, error)                                         // Count Payments recorded against the Histories and Reservations of Customer
+	CountOpenReservationsByCustomer(customer *model.Customer) (int64, error)                                 // Count tentative, confirmed and checked-in Reservations of Customer
+	CountInvoicesByCustomer(customer *model.Customer) (int64, error)                                         // Count Invoices issued to Customer or for their Histories or BookingGroups
+	AnonymizeCustomer(customer *model.Customer, log *model.AuditLog) error                                   // Anonymize Customer identifying data and the notes tied to them, and record the action
+	ListReservationsByCustomer(customer *model.Customer) ([]*model.Reservation, error)                       // Get Reservations of Customer
+	ListPaymentsByCustomer(customer *model.Customer) ([]*model.Payment, error)                               // Get Payments of the Histories and Reservations of Customer
+	ListCompanionStays(customer *model.Customer) ([]*model.History, error)                                   // Get Histories of other Customers where Customer is a guest
+	ListInvoicesByCustomer(customer *model.Customer) ([]*model.Invoice, error)                               // Get Invoices issued to Customer
+	ListRegistrationSubmissionsByCustomer(customer *model.Customer) ([]*model.RegistrationSubmission, error) // Get RegistrationSubmissions reporting Customer
+	ListAuditLogs(log *model.AuditLog) ([]*model.AuditLog, error)                                            // Get AuditLogs by Entity and EntityId
+	CreateAuditLog(log *model.AuditLog) error                                                                // Create a new AuditLog
+	ListCustomersByNationalIds(nationalIds []string) ([]*model.Customer, error)                              // Get Customers by a list of NationalIds
+	UpsertCustomers(customers []*model.Customer) error                                                       // Create or update Customers by NationalId, all or none
+	ListCitizenships() ([]*model.Citizenship, error)                                                         // Get all Citizenships
+	StreamCustomers(filter *dto.CustomerFilter, fn func(customer *model.Customer) error) error               // Iterate Customers matching the filter in batches
 }
 
 // CustomerService is an interface for customer service
 type CustomerService interface {
-	ListCustomers() ([]*model.Customer, error)                             // Get all Customers
-	ListCustomersByCitizenship(citizenship int) ([]*model.Customer, error) // Get all Customers by citizenship
-	ListCustomersByName(name string) ([]*model.Customer, error)            // Get Customer by customer_name
-	ListCustomersByPhone(phone string) ([]*model.Customer, error)          // Get Customer by customer_phone
-	GetCustomerByNationalId(id string) (*model.Customer, error)            // Get Customer by ID
-	GetCustomerByCustomerId(customerId uuid.UUID) (*model.Customer, error) // Get Customer by customer_id
-	CreateCustomer(customer *model.Customer) (*model.Customer, error)      // Create a new Customer
-	UpdateCustomer(customer *model.Customer) (*model.Customer, error)      // Update customer data
-	DeleteCustomer(customerId uuid.UUID) error                             // Delete Customer by customer_id
+	ListCustomers() ([]*model.Customer, error)                                                     // Get all Customers
+	ListCustomersByCitizenship(citizenship int) ([]*model.Customer, error)                         // Get all Customers by citizenship
+	ListCustomersByName(name string) ([]*model.Customer, error)                                    // Get Customer by customer_name
+	ListCustomersByPhone(phone string) ([]*model.Customer, error)                                  // Get Customer by customer_phone
+	GetCustomerByNationalId(id string) (*model.Customer, error)                                    // Get Customer by ID
+	GetCustomerByCustomerId(customerId uuid.UUID) (*model.Customer, error)                         // Get Customer by customer_id
+	CreateCustomer(customer *model.Customer) (*model.Customer, error)                              // Create a new Customer
+	UpdateCustomer(customer *model.Customer) (*model.Customer, error)                              // Update customer data
+	DeleteCustomer(customerId uuid.UUID) error                                                     // Delete Customer by customer_id
+	ExportCustomerData(customerId uuid.UUID) (*dto.CustomerDataPackage, error)                     // Export all data of Customer by customer_id
+	EraseCustomer(customerId uuid.UUID, reason string) error                                       // Anonymize Customer by customer_id
+	ImportCustomers(rows []*dto.CustomerImportRow, dryRun bool) (*dto.CustomerImportResult, error) // Create or update Customers from imported rows
+	StreamCustomers(filter *dto.CustomerFilter, fn func(customer *model.Customer) error) error     // Iterate Customers matching the filter
 }
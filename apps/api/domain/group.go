@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+)
+
+// BookingGroupRepository is an interface for booking group repository
+type BookingGroupRepository interface {
+	ListGroups() ([]*model.BookingGroup, error)                                           // Get all BookingGroups with their organizers
+	GetGroupById(group *model.BookingGroup) (*model.BookingGroup, error)                  // Get BookingGroup by GroupId
+	CreateGroup(group *model.BookingGroup) (*model.BookingGroup, error)                   // Create a new BookingGroup
+	UpdateGroup(group *model.BookingGroup) (*model.BookingGroup, error)                   // Update BookingGroup data
+	DeleteGroup(group *model.BookingGroup) error                                          // Delete BookingGroup by GroupId
+	ListReservationsByGroup(reservation *model.Reservation) ([]*model.Reservation, error) // Get the Reservations of a BookingGroup
+	ListHistoriesByGroup(history *model.History) ([]*model.History, error)                // Get the stays and fees of a BookingGroup
+	MoveReservations(reservations []*model.Reservation) error                             // Save the new dates of Reservations moved together
+	ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error)           // Confirm Customer Existed
+}
+
+// BookingGroupService is an interface for booking group service
+type BookingGroupService interface {
+	ListGroups() ([]*model.BookingGroup, error)                                     // Get all BookingGroups
+	GetGroupById(groupId uuid.UUID) (*model.BookingGroup, error)                    // Get BookingGroup by GroupId
+	CreateGroup(group *model.BookingGroup) (*model.BookingGroup, error)             // Create a new BookingGroup
+	UpdateGroup(group *model.BookingGroup) (*model.BookingGroup, error)             // Update BookingGroup data
+	DeleteGroup(groupId uuid.UUID) error                                            // Delete a BookingGroup without reservations or stays
+	ListGroupStays(groupId uuid.UUID) (*dto.GroupStays, error)                      // Get the Reservations and stays of a BookingGroup
+	ConfirmGroup(groupId uuid.UUID) (*dto.GroupOperationResult, error)              // Confirm every tentative Reservation of a BookingGroup
+	CancelGroup(groupId uuid.UUID) (*dto.GroupOperationResult, error)               // Cancel every open Reservation of a BookingGroup, charging their fees
+	CheckInGroup(groupId uuid.UUID) (*dto.GroupOperationResult, error)              // Check in every confirmed Reservation of a BookingGroup
+	MoveGroupDates(groupId uuid.UUID, checkIn string) ([]*model.Reservation, error) // Move every open Reservation of a BookingGroup so the earliest checks in on checkIn
+}
@@ -2,32 +2,44 @@ package domain
 
 import (
 	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
 	"github.com/google/uuid"
+	"time"
 )
 
 // HistoryRepository is an interface for History repository
 type HistoryRepository interface {
-	ListHistories() ([]*model.History, error)                                                          // Get all History
-	ListHistoriesByCustomer(history *model.History) ([]*model.History, error)                          // Get History by CustomerId
-	ListHistoriesForDate(history *model.History) ([]*model.History, error)                             // Get History by Date
-	ListHistoriesForDuring(history1 *model.History, history2 *model.History) ([]*model.History, error) // Get History by During
-	GetHistoryByHistoryId(history *model.History) (*model.History, error)                              // Get History by HistoryID
-	CreateHistory(history *model.History) (*model.History, error)                                      // Create a new History
-	UpdateHistory(history *model.History) (*model.History, error)                                      // Update History data
-	DeleteHistory(history *model.History) error                                                        // Delete History by HistoryID
-	DeleteHistoriesByCustomer(history *model.History) error                                            // Delete History by CustomerID
-	ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error)                        // Confirm Customer Existed
+	ListHistories() ([]*model.History, error)                                                                               // Get all History
+	ListHistoriesByCustomer(history *model.History) ([]*model.History, error)                                               // Get History by CustomerId
+	ListHistoriesForDate(history *model.History) ([]*model.History, error)                                                  // Get stays on the night of Date, without fees
+	ListHistoriesForDuring(history1 *model.History, history2 *model.History) ([]*model.History, error)                      // Get stays overlapping During, without fees
+	GetHistoryByHistoryId(history *model.History) (*model.History, error)                                                   // Get History by HistoryID
+	CreateHistory(history *model.History) (*model.History, error)                                                           // Create a new History
+	UpdateHistory(history *model.History) (*model.History, error)                                                           // Update History data
+	MoveHistoryRoom(history *model.History) (*model.History, error)                                                         // Save the StaySegments of History after a room move
+	DeleteHistory(history *model.History) error                                                                             // Delete History by HistoryID
+	DeleteHistoriesByCustomer(history *model.History) error                                                                 // Delete History by CustomerID
+	ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error)                                             // Confirm Customer Existed
+	ConfirmRoomExistence(room *model.Room) (*model.Room, error)                                                             // Confirm Room Existed by RoomId or Number
+	ConfirmGroupExistence(group *model.BookingGroup) (*model.BookingGroup, error)                                           // Confirm BookingGroup Existed
+	CountPaymentsByHistory(history *model.History) (int64, error)                                                           // Count Payments recorded against History
+	CountInvoicesByHistory(history *model.History) (int64, error)                                                           // Count Invoices issued for History or its BookingGroup
+	CountPaymentsByCustomer(history *model.History) (int64, error)                                                          // Count Payments recorded against the Histories and Reservations of CustomerId
+	CountInvoicesByCustomer(history *model.History) (int64, error)                                                          // Count Invoices issued to CustomerId or for their Histories or BookingGroups
+	StreamHistories(filter *dto.HistoryFilter, start time.Time, end time.Time, fn func(history *model.History) error) error // Iterate History matching the filter in batches
 }
 
 // HistoryService is an interface for History service
 type HistoryService interface {
-	ListHistories() ([]*model.History, error)                                // Get all History
-	ListHistoriesByCustomerId(in uuid.UUID) ([]*model.History, error)        // Get History by CustomerId
-	ListHistoriesForDate(in string) ([]*model.History, error)                // Get History by Date
-	ListHistoriesForDuring(in1 string, in2 string) ([]*model.History, error) // Get History by During
-	GetHistoryByHistoryId(in uuid.UUID) (*model.History, error)              // Get History by HistoryId
-	CreateHistory(in *model.History) (*model.History, error)                 // Create a new History
-	UpdateHistory(in *model.History) (*model.History, error)                 // Update History data
-	DeleteHistory(in uuid.UUID) error                                        // Delete History by ID
-	DeleteHistoriesByCustomer(in uuid.UUID) error                            // Delete History by CustomerID
+	ListHistories() ([]*model.History, error)                                               // Get all History
+	ListHistoriesByCustomerId(in uuid.UUID) ([]*model.History, error)                       // Get History by CustomerId
+	ListHistoriesForDate(in string) ([]*model.History, error)                               // Get stays on the night of Date, without fees
+	ListHistoriesForDuring(in1 string, in2 string) ([]*model.History, error)                // Get stays overlapping During, without fees
+	GetHistoryByHistoryId(in uuid.UUID) (*model.History, error)                             // Get History by HistoryId
+	CreateHistory(in *model.History) (*model.History, error)                                // Create a new History
+	UpdateHistory(in *model.History) (*model.History, error)                                // Update History data
+	MoveHistoryRoom(in uuid.UUID, room *model.Room, date string) (*model.History, error)    // Move History to another room from date until check-out
+	DeleteHistory(in uuid.UUID) error                                                       // Delete History by ID
+	DeleteHistoriesByCustomer(in uuid.UUID) error                                           // Delete History by CustomerID
+	StreamHistories(filter *dto.HistoryFilter, fn func(history *model.History) error) error // Iterate History matching the filter
 }
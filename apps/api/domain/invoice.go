@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+)
+
+// InvoiceRepository is an interface for invoice repository
+type InvoiceRepository interface {
+	ListInvoices() ([]*model.Invoice, error)                                                              // Get all Invoices and credit notes
+	ListInvoicesByHistory(invoice *model.Invoice) ([]*model.Invoice, error)                               // Get Invoices of a stay
+	ListInvoicesByGroup(invoice *model.Invoice) ([]*model.Invoice, error)                                 // Get Invoices of a booking group
+	GetInvoiceById(invoice *model.Invoice) (*model.Invoice, error)                                        // Get Invoice by InvoiceId
+	CreateInvoice(invoice *model.Invoice, series string) (*model.Invoice, error)                          // Number and create a new Invoice unless its stays already have one in force
+	VoidInvoice(invoice *model.Invoice, creditNote *model.Invoice, series string) (*model.Invoice, error) // Void Invoice with a numbered credit note
+	GetHistoryForInvoice(history *model.History) (*model.History, error)                                  // Get History with its room and tax lines
+	GetGroupForInvoice(group *model.BookingGroup) (*model.BookingGroup, error)                            // Get BookingGroup with its organizer
+	ListHistoriesByGroup(history *model.History) ([]*model.History, error)                                // Get the stays and fees of a booking group with their rooms and tax lines
+	SumPaymentsByHistory(history *model.History) (int64, error)                                           // Get the amount paid for History, refunds deducted
+	ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error)                           // Confirm Customer Existed
+}
+
+// InvoiceService is an interface for invoice service
+type InvoiceService interface {
+	ListInvoices() ([]*model.Invoice, error)                                  // Get all Invoices and credit notes
+	ListInvoicesByHistoryId(historyId uuid.UUID) ([]*model.Invoice, error)    // Get Invoices of a stay
+	GetInvoiceById(invoiceId uuid.UUID) (*model.Invoice, error)               // Get Invoice by InvoiceId
+	ListInvoicesByGroupId(groupId uuid.UUID) ([]*model.Invoice, error)        // Get Invoices of a booking group
+	IssueInvoice(historyId uuid.UUID, note string) (*model.Invoice, error)    // Issue an Invoice from the price breakdown and payments of a stay
+	IssueGroupInvoice(groupId uuid.UUID, note string) (*model.Invoice, error) // Issue one Invoice for every stay of a booking group to its organizer
+	VoidInvoice(invoiceId uuid.UUID, reason string) (*model.Invoice, error)   // Void Invoice and return its credit note
+}
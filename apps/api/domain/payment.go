@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+)
+
+// PaymentRepository is an interface for payment repository
+type PaymentRepository interface {
+	ListPaymentsByHistory(payment *model.Payment) ([]*model.Payment, error)                 // Get Payments of a stay
+	ListPaymentsByReservation(payment *model.Payment) ([]*model.Payment, error)             // Get deposits of a Reservation
+	CreatePayment(payment *model.Payment) (*model.Payment, error)                           // Create a new Payment
+	ListStayBalances(filter *dto.StayBalanceFilter) ([]*dto.StayBalance, error)             // Get the charged and paid amounts of stays
+	ConfirmHistoryExistence(history *model.History) (*model.History, error)                 // Confirm History Existed
+	ConfirmReservationExistence(reservation *model.Reservation) (*model.Reservation, error) // Confirm Reservation Existed
+	ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error)             // Confirm Customer Existed
+}
+
+// PaymentService is an interface for payment service
+type PaymentService interface {
+	ListPaymentsByHistoryId(historyId uuid.UUID) ([]*model.Payment, error)         // Get Payments of a stay
+	ListPaymentsByReservationId(reservationId uuid.UUID) ([]*model.Payment, error) // Get deposits of a Reservation
+	CreatePayment(payment *model.Payment) (*model.Payment, error)                  // Record a payment or a refund
+	GetStayBalance(historyId uuid.UUID) (*dto.StayBalance, error)                  // Get the outstanding balance of a stay
+	GetCustomerBalance(customerId uuid.UUID) (*dto.CustomerBalance, error)         // Get the outstanding balance of a Customer
+	ListUnpaidStays() ([]*dto.StayBalance, error)                                  // Get stays not paid in full
+}
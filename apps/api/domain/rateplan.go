@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ErrNoRatePlan is returned by RatePlanService.QuoteStay when the room type has no active rate plan to pick
+var ErrNoRatePlan = errors.New("error CRMS : There is no rate plan for this room type")
+
+// RatePlanRepository is an interface for rate plan repository
+type RatePlanRepository interface {
+	ListRatePlans() ([]*model.RatePlan, error)                          // Get all RatePlans with their seasons
+	ListRatePlansByRoomType(roomType string) ([]*model.RatePlan, error) // Get active RatePlans of a room type
+	GetRatePlanById(plan *model.RatePlan) (*model.RatePlan, error)      // Get RatePlan by RatePlanId
+	CreateRatePlan(plan *model.RatePlan) (*model.RatePlan, error)       // Create a new RatePlan with its seasons
+	UpdateRatePlan(plan *model.RatePlan) (*model.RatePlan, error)       // Update RatePlan data, replacing its seasons
+	DeleteRatePlan(plan *model.RatePlan) error                          // Delete RatePlan and its seasons
+	CountHistoriesByRatePlan(plan *model.RatePlan) (int64, error)       // Count Histories priced with RatePlan
+	ConfirmRoomExistence(room *model.Room) (*model.Room, error)         // Confirm Room Existed by RoomId or Number
+}
+
+// RatePlanService is an interface for rate plan service
+type RatePlanService interface {
+	ListRatePlans() ([]*model.RatePlan, error)                                                                                                                // Get all RatePlans
+	GetRatePlanById(planId uuid.UUID) (*model.RatePlan, error)                                                                                                // Get RatePlan by RatePlanId
+	CreateRatePlan(plan *model.RatePlan) (*model.RatePlan, error)                                                                                             // Create a new RatePlan
+	UpdateRatePlan(plan *model.RatePlan) (*model.RatePlan, error)                                                                                             // Update RatePlan data
+	DeleteRatePlan(planId uuid.UUID) error                                                                                                                    // Delete RatePlan by RatePlanId
+	QuoteStay(planId uuid.UUID, currentPlanId uuid.UUID, room *model.Room, checkIn time.Time, checkOut time.Time, numberOfPeople int) (*dto.StayQuote, error) // Price a stay night by night, with the cheapest plan of the room type when planId is empty; an inactive planId only for a stay already on it, currentPlanId
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"time"
+)
+
+// RegistrationRepository is an interface for registration repository
+type RegistrationRepository interface {
+	ListRegistrationGuests(domestic string, date time.Time) ([]*dto.RegistrationRow, error) // Get the guests in house the night of date whose Citizenship is not domestic
+	CreateRegistrationSubmissions(submissions []*model.RegistrationSubmission) error        // Record guests as reported, replacing earlier records of the same guests
+}
+
+// RegistrationService is an interface for registration service
+type RegistrationService interface {
+	ListRegistrations(date string, unsubmitted bool) ([]*dto.RegistrationRow, error)                 // Get the foreign guests of a night with their missing fields
+	ExportRegistrations(date string, resubmit bool) ([]*dto.RegistrationRow, error)                  // Get the foreign guests of a night ready to report
+	SubmitRegistrations(date string, guests []dto.RegistrationGuest) ([]*dto.RegistrationRow, error) // Record foreign guests of a night as reported
+}
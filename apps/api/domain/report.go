@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ReportRepository is an interface for report repository
+type ReportRepository interface {
+	SumRevenue(currency string, start time.Time, end time.Time) (*dto.RevenueFigures, error)                                                    // Sum revenue of the nights of Histories between start and end
+	SumRevenueByPeriod(format string, currency string, start time.Time, end time.Time) ([]*dto.RevenuePeriod, error)                            // Sum revenue per period of the nights, format being a MySQL date format
+	SumRevenueByRoom(currency string, start time.Time, end time.Time) ([]*dto.RevenueBreakdown, error)                                          // Sum revenue per Room
+	SumRevenueByCitizenship(currency string, start time.Time, end time.Time) ([]*dto.RevenueBreakdown, error)                                   // Sum revenue per Citizenship of the customer
+	SumRevenueByCurrency(currency string, start time.Time, end time.Time) ([]*dto.RevenueCurrency, error)                                       // Sum revenue per Currency other than currency
+	ListKpiDays(roomType string, currency string, start time.Time, end time.Time) ([]*dto.KpiDay, error)                                        // Get rooms available, rooms occupied and room revenue of every night
+	CountGuestsByCitizenship(start time.Time, end time.Time) ([]*dto.NationalityRow, error)                                                     // Count the guests and guest-nights between start and end per Citizenship
+	SumCustomerValue(customerId uuid.UUID, currency string) (*dto.CustomerValue, error)                                                         // Sum stays, nights and spend of every History of a customer
+	ListTopGuests(orderBy string, limit int, currency string, start time.Time, end time.Time) ([]*dto.CustomerValue, error)                     // Get the customers with the highest orderBy column over the Histories checking in between start and end, every History when start is zero
+	CountRepeatGuests(start time.Time, end time.Time) (*dto.RepeatRateFigures, error)                                                           // Count the guests checking in between start and end and those of them staying a second time
+	ListBookedDays(statuses []string, currency string, createdBefore time.Time, start time.Time, end time.Time) ([]*dto.ForecastFigures, error) // Get the rooms and room revenue of the Reservations in statuses of every night, only those created before createdBefore unless it is zero
+	CountRepeatGuestsByPeriod(format string, start time.Time, end time.Time) ([]*dto.RepeatRatePeriod, error)                                   // Count guests and repeat guests per check-in period, format being a MySQL date format
+}
+
+// ReportService is an interface for report service
+type ReportService interface {
+	RevenueReport(in *dto.RevenueReportRequest) (*dto.RevenueReport, error)             // Get revenue, stays and guests per period with breakdowns and the change from the periods before
+	KpiReport(in *dto.KpiReportRequest) (*dto.KpiReport, error)                         // Get occupancy, ADR and RevPAR in total and per night
+	NationalityReport(in *dto.NationalityReportRequest) (*dto.NationalityReport, error) // Get guests and guest-nights per citizenship
+	CustomerValue(in *dto.CustomerValueRequest) (*dto.CustomerValue, error)             // Get the lifetime stays, nights and spend of a customer
+	TopGuests(in *dto.TopGuestsRequest) (*dto.TopGuests, error)                         // Get the best customers by spend, stays or nights
+	Forecast(in *dto.ForecastRequest) (*dto.Forecast, error)                            // Get on-the-books and projected occupancy and room revenue of the coming nights
+	RepeatRateReport(in *dto.RepeatRateRequest) (*dto.RepeatRateReport, error)          // Get the share of guests staying a second time per period
+}
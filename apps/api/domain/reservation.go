@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+)
+
+// ReservationRepository is an interface for reservation repository
+type ReservationRepository interface {
+	ListReservations() ([]*model.Reservation, error)                                                                          // Get all Reservations
+	ListReservationsByCustomer(reservation *model.Reservation) ([]*model.Reservation, error)                                  // Get Reservations by CustomerId
+	ListReservationsForDuring(reservation1 *model.Reservation, reservation2 *model.Reservation) ([]*model.Reservation, error) // Get Reservations overlapping During
+	GetReservationByReservationId(reservation *model.Reservation) (*model.Reservation, error)                                 // Get Reservation by ReservationId
+	CreateReservation(reservation *model.Reservation) (*model.Reservation, error)                                             // Create a new Reservation
+	UpdateReservation(reservation *model.Reservation) (*model.Reservation, error)                                             // Update Reservation data
+	UpdateReservationStatus(reservation *model.Reservation) (*model.Reservation, error)                                       // Update Reservation status
+	CheckInReservation(reservation *model.Reservation, history *model.History) (*model.Reservation, error)                    // Create the History of a Reservation and mark it checked in
+	GetReservationHistory(history *model.History) (*model.History, error)                                                     // Get the History of a Reservation with its TaxLines
+	CheckOutReservation(reservation *model.Reservation, history *model.History) (*model.Reservation, error)                   // Close the History of a Reservation, repriced after an early departure, and mark it checked out
+	CancelReservation(reservation *model.Reservation, fee *model.History) (*model.Reservation, error)                         // Mark Reservation cancelled or no-show, posting its fee when one is charged
+	GetCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error)                                // Get CancellationPolicy by PolicyId, or the default one when PolicyId is empty
+	ConfirmGroupExistence(group *model.BookingGroup) (*model.BookingGroup, error)                                             // Confirm BookingGroup Existed
+	ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error)                                               // Confirm Customer Existed
+	ConfirmRoomExistence(room *model.Room) (*model.Room, error)                                                               // Confirm Room Existed
+}
+
+// ReservationService is an interface for reservation service
+type ReservationService interface {
+	ListReservations() ([]*model.Reservation, error)                                 // Get all Reservations
+	ListReservationsByCustomerId(in uuid.UUID) ([]*model.Reservation, error)         // Get Reservations by CustomerId
+	ListReservationsForDuring(in1 string, in2 string) ([]*model.Reservation, error)  // Get Reservations overlapping During
+	GetReservationByReservationId(in uuid.UUID) (*model.Reservation, error)          // Get Reservation by ReservationId
+	CreateReservation(in *model.Reservation) (*model.Reservation, error)             // Create a new Reservation
+	UpdateReservation(in *model.Reservation) (*model.Reservation, error)             // Update Reservation data
+	ChangeReservationStatus(in uuid.UUID, status string) (*model.Reservation, error) // Move Reservation to another status, charging the cancellation or no-show fee
+	CheckInReservation(in uuid.UUID) (*model.Reservation, error)                     // Check in Reservation and create its History
+	CheckOutReservation(in uuid.UUID) (*model.Reservation, error)                    // Check out Reservation and complete its History
+}
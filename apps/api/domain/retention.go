@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+// RetentionRepository is an interface for retention repository
+type RetentionRepository interface {
+	ListRetentionRules() ([]*model.RetentionRule, error)                                                                     // Get all RetentionRules
+	GetRetentionRuleById(rule *model.RetentionRule) (*model.RetentionRule, error)                                            // Get RetentionRule by ID
+	CreateRetentionRule(rule *model.RetentionRule) (*model.RetentionRule, error)                                             // Create a new RetentionRule
+	UpdateRetentionRule(rule *model.RetentionRule) (*model.RetentionRule, error)                                             // Update RetentionRule data
+	DeleteRetentionRule(rule *model.RetentionRule) error                                                                     // Delete RetentionRule by ID
+	ListExpiredCustomers(rule *model.RetentionRule, cutoff time.Time, excludedCitizenships []int) ([]*model.Customer, error) // Get Customers whose last stay or reservation, or creation without either, is before cutoff, without invoices, payments or open reservations when purging
+	ListExpiredHistories(rule *model.RetentionRule, cutoff time.Time, excludedCitizenships []int) ([]*model.History, error)  // Get Histories dated before cutoff, without invoices or payments when purging
+	PurgeCustomers(customerIds []uuid.UUID, logs []*model.AuditLog) error                                                    // Delete Customers with their Histories and Reservations
+	PurgeHistories(historyIds []uuid.UUID, logs []*model.AuditLog) error                                                     // Delete Histories with their Reservations
+	AnonymizeHistories(historyIds []uuid.UUID, logs []*model.AuditLog) error                                                 // Clear free text of Histories
+}
+
+// RetentionService is an interface for retention service
+type RetentionService interface {
+	ListRetentionRules() ([]*model.RetentionRule, error)                         // Get all RetentionRules
+	CreateRetentionRule(rule *model.RetentionRule) (*model.RetentionRule, error) // Create a new RetentionRule
+	UpdateRetentionRule(rule *model.RetentionRule) (*model.RetentionRule, error) // Update RetentionRule data
+	DeleteRetentionRule(ruleId uuid.UUID) error                                  // Delete RetentionRule by ID
+	DryRun() (*dto.RetentionReport, error)                                       // Report what the RetentionRules would affect
+	ApplyRetentionRules() (*dto.RetentionReport, error)                          // Anonymize or purge expired data
+}
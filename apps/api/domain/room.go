@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+// RoomRepository is an interface for room repository
+type RoomRepository interface {
+	ListRooms() ([]*model.Room, error)                                                                   // Get all Rooms
+	GetRoomByRoomId(room *model.Room) (*model.Room, error)                                               // Get Room by RoomId
+	GetRoomByNumber(room *model.Room) (*model.Room, error)                                               // Get Room by Number
+	CreateRoom(room *model.Room) (*model.Room, error)                                                    // Create a new Room
+	UpdateRoom(room *model.Room) (*model.Room, error)                                                    // Update Room data
+	DeleteRoom(room *model.Room) error                                                                   // Delete Room by RoomId
+	CountHistoriesByRoom(room *model.Room) (int64, error)                                                // Count Histories staying in Room
+	CountReservationsByRoom(room *model.Room) (int64, error)                                             // Count Reservations booked in Room
+	ListAvailableRooms(checkIn time.Time, checkOut time.Time, numberOfPeople int) ([]*model.Room, error) // Get active Rooms free and in service for the whole stay
+	ListOccupancy(start time.Time, end time.Time) ([]*dto.OccupancyStay, error)                          // Get stays and open reservations overlapping the period
+	ListRoomOutages(outage *model.RoomOutage) ([]*model.RoomOutage, error)                               // Get RoomOutages by RoomId, all of them when RoomId is empty
+	GetRoomOutageById(outage *model.RoomOutage) (*model.RoomOutage, error)                               // Get RoomOutage by OutageId
+	CreateRoomOutage(outage *model.RoomOutage) (*model.RoomOutage, error)                                // Create a new RoomOutage over nights no stay, open reservation or outage holds
+	DeleteRoomOutage(outage *model.RoomOutage) error                                                     // Delete RoomOutage by OutageId
+}
+
+// RoomService is an interface for room service
+type RoomService interface {
+	ListRooms() ([]*model.Room, error)                                                                         // Get all Rooms
+	GetRoomByRoomId(roomId uuid.UUID) (*model.Room, error)                                                     // Get Room by RoomId
+	GetRoomByNumber(number string) (*model.Room, error)                                                        // Get Room by Number
+	CreateRoom(room *model.Room) (*model.Room, error)                                                          // Create a new Room
+	UpdateRoom(room *model.Room) (*model.Room, error)                                                          // Update Room data
+	DeleteRoom(roomId uuid.UUID) error                                                                         // Delete Room by RoomId
+	SearchAvailableRooms(checkIn string, checkOut string, numberOfPeople int) ([]*dto.RoomAvailability, error) // Get Rooms free for the whole stay quoted by their rate plans
+	GetOccupancyCalendar(startDate string, endDate string) (*dto.OccupancyCalendar, error)                     // Get the room by night grid of the period
+	ListRoomOutages(roomId uuid.UUID) ([]*model.RoomOutage, error)                                             // Get RoomOutages of Room, all of them when RoomId is empty
+	CreateRoomOutage(outage *model.RoomOutage) (*model.RoomOutage, error)                                      // Take Room out of service
+	DeleteRoomOutage(outageId uuid.UUID) error                                                                 // Delete RoomOutage by OutageId
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+)
+
+// StayGuestRepository is an interface for stay guest repository
+type StayGuestRepository interface {
+	ListStayGuests(guest *model.StayGuest) ([]*model.StayGuest, error)                     // Get the guests of a stay, primary guest first
+	CreateStayGuest(guest *model.StayGuest, seat func(history *model.History) error) error // Add a guest to a stay once seat accepts it, saving the people and charges seat sets
+	DeleteStayGuest(guest *model.StayGuest) error                                          // Remove a guest from a stay
+	SetPrimaryGuest(guest *model.StayGuest) error                                          // Make a guest the primary guest and customer of the stay
+	ListTravelCompanions(customer *model.Customer) ([]*dto.TravelCompanion, error)         // Get the customers who shared a stay with Customer
+	ConfirmHistoryExistence(history *model.History) (*model.History, error)                // Confirm History Existed
+	ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error)            // Confirm Customer Existed
+}
+
+// StayGuestService is an interface for stay guest service
+type StayGuestService interface {
+	ListStayGuests(historyId uuid.UUID) ([]*model.StayGuest, error)                        // Get the guests of a stay, primary guest first
+	AddStayGuest(historyId uuid.UUID, customerId uuid.UUID) ([]*model.StayGuest, error)    // Add a companion to a stay
+	RemoveStayGuest(historyId uuid.UUID, customerId uuid.UUID) ([]*model.StayGuest, error) // Remove a companion from a stay
+	SetPrimaryGuest(historyId uuid.UUID, customerId uuid.UUID) ([]*model.StayGuest, error) // Make a guest the primary guest of a stay
+	ListTravelCompanions(customerId uuid.UUID) ([]*dto.TravelCompanion, error)             // Get the customers who shared a stay with Customer, most frequent first
+}
@@ -15,15 +15,48 @@ import (
 	_ "github.com/S1nceU/CRMS/apps/api/docs"
 	"github.com/S1nceU/CRMS/apps/api/model"
 
+	_cancellationHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/cancellation/delivery/http"
+	_cancellationRepo "github.com/S1nceU/CRMS/apps/api/module/cancellation/repository"
+	_cancellationSer "github.com/S1nceU/CRMS/apps/api/module/cancellation/service"
 	_citizenshipHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/citizenship/delivery/http"
 	_citizenshipRepo "github.com/S1nceU/CRMS/apps/api/module/citizenship/repository"
 	_citizenshipSer "github.com/S1nceU/CRMS/apps/api/module/citizenship/service"
 	_customerHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/customer/delivery/http"
 	_customerRepo "github.com/S1nceU/CRMS/apps/api/module/customer/repository"
 	_customerSer "github.com/S1nceU/CRMS/apps/api/module/customer/service"
+	_groupHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/group/delivery/http"
+	_groupRepo "github.com/S1nceU/CRMS/apps/api/module/group/repository"
+	_groupSer "github.com/S1nceU/CRMS/apps/api/module/group/service"
 	_historyHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/history/delivery/http"
 	_historyRepo "github.com/S1nceU/CRMS/apps/api/module/history/repository"
 	_historySer "github.com/S1nceU/CRMS/apps/api/module/history/service"
+	_invoiceHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/invoice/delivery/http"
+	_invoiceRepo "github.com/S1nceU/CRMS/apps/api/module/invoice/repository"
+	_invoiceSer "github.com/S1nceU/CRMS/apps/api/module/invoice/service"
+	_paymentHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/payment/delivery/http"
+	_paymentRepo "github.com/S1nceU/CRMS/apps/api/module/payment/repository"
+	_paymentSer "github.com/S1nceU/CRMS/apps/api/module/payment/service"
+	_ratePlanHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/rateplan/delivery/http"
+	_ratePlanRepo "github.com/S1nceU/CRMS/apps/api/module/rateplan/repository"
+	_ratePlanSer "github.com/S1nceU/CRMS/apps/api/module/rateplan/service"
+	_registrationHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/registration/delivery/http"
+	_registrationRepo "github.com/S1nceU/CRMS/apps/api/module/registration/repository"
+	_registrationSer "github.com/S1nceU/CRMS/apps/api/module/registration/service"
+	_reportHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/report/delivery/http"
+	_reportRepo "github.com/S1nceU/CRMS/apps/api/module/report/repository"
+	_reportSer "github.com/S1nceU/CRMS/apps/api/module/report/service"
+	_reservationHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/reservation/delivery/http"
+	_reservationRepo "github.com/S1nceU/CRMS/apps/api/module/reservation/repository"
+	_reservationSer "github.com/S1nceU/CRMS/apps/api/module/reservation/service"
+	_retentionHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/retention/delivery/http"
+	_retentionRepo "github.com/S1nceU/CRMS/apps/api/module/retention/repository"
+	_retentionSer "github.com/S1nceU/CRMS/apps/api/module/retention/service"
+	_roomHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/room/delivery/http"
+	_roomRepo "github.com/S1nceU/CRMS/apps/api/module/room/repository"
+	_roomSer "github.com/S1nceU/CRMS/apps/api/module/room/service"
+	_stayGuestHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/stayguest/delivery/http"
+	_stayGuestRepo "github.com/S1nceU/CRMS/apps/api/module/stayguest/repository"
+	_stayGuestSer "github.com/S1nceU/CRMS/apps/api/module/stayguest/service"
 	_userHandlerHttpDelivery "github.com/S1nceU/CRMS/apps/api/module/user/delivery/http"
 	_userRepo "github.com/S1nceU/CRMS/apps/api/module/user/repository"
 	_userSer "github.com/S1nceU/CRMS/apps/api/module/user/service"
@@ -84,15 +117,69 @@ func init() {
 			config.ImportCitizenshipData(db)
 			log.Println("Init citizenship data successfully")
 		}
+		if err = config.MigrateCustomerCreatedAt(db); err != nil {
+			log.Fatal("There was an error migrating customers, due to " + err.Error())
+		}
 		if err = db.AutoMigrate(&model.Customer{}); err != nil {
 			return
 		}
-		if err = db.AutoMigrate(&model.History{}); err != nil {
+		if err = db.AutoMigrate(&model.Room{}, &model.RoomOutage{}); err != nil {
+			return
+		}
+		if err = config.MigrateHistoryStayDates(db); err != nil {
+			log.Fatal("There was an error migrating histories, due to " + err.Error())
+		}
+		if err = config.MigrateHistoryRooms(db); err != nil {
+			log.Fatal("There was an error migrating histories, due to " + err.Error())
+		}
+		if err = db.AutoMigrate(&model.RatePlan{}, &model.RatePlanSeason{}); err != nil {
+			return
+		}
+		if err = db.AutoMigrate(&model.BookingGroup{}); err != nil {
+			return
+		}
+		if err = db.AutoMigrate(&model.History{}, &model.HistoryTaxLine{}); err != nil {
+			return
+		}
+		if err = config.MigrateHistoryMoney(db); err != nil {
+			log.Fatal("There was an error migrating histories, due to " + err.Error())
+		}
+		if err = db.AutoMigrate(&model.StayGuest{}); err != nil {
+			return
+		}
+		if err = config.MigrateStayGuests(db); err != nil {
+			log.Fatal("There was an error migrating histories, due to " + err.Error())
+		}
+		if err = db.AutoMigrate(&model.StaySegment{}); err != nil {
+			return
+		}
+		if err = config.MigrateStaySegments(db); err != nil {
+			log.Fatal("There was an error migrating histories, due to " + err.Error())
+		}
+		if err = db.AutoMigrate(&model.RegistrationSubmission{}); err != nil {
+			return
+		}
+		if err = db.AutoMigrate(&model.CancellationPolicy{}); err != nil {
+			return
+		}
+		if err = db.AutoMigrate(&model.Reservation{}); err != nil {
+			return
+		}
+		if err = db.AutoMigrate(&model.Payment{}); err != nil {
+			return
+		}
+		if err = db.AutoMigrate(&model.Invoice{}, &model.InvoiceLine{}, &model.InvoiceSequence{}); err != nil {
 			return
 		}
 		if err = db.AutoMigrate(&model.User{}); err != nil {
 			return
 		}
+		if err = db.AutoMigrate(&model.AuditLog{}); err != nil {
+			return
+		}
+		if err = db.AutoMigrate(&model.RetentionRule{}); err != nil {
+			return
+		}
 	}
 }
 
@@ -106,16 +193,49 @@ func main() {
 	historyRepo := _historyRepo.NewHistoryRepository(db)
 	userRepo := _userRepo.NewUserRepository(db)
 	citizenshipRepo := _citizenshipRepo.NewCitizenshipRepository(db)
+	retentionRepo := _retentionRepo.NewRetentionRepository(db)
+	roomRepo := _roomRepo.NewRoomRepository(db)
+	reservationRepo := _reservationRepo.NewReservationRepository(db)
+	ratePlanRepo := _ratePlanRepo.NewRatePlanRepository(db)
+	paymentRepo := _paymentRepo.NewPaymentRepository(db)
+	invoiceRepo := _invoiceRepo.NewInvoiceRepository(db)
+	stayGuestRepo := _stayGuestRepo.NewStayGuestRepository(db)
+	cancellationRepo := _cancellationRepo.NewCancellationPolicyRepository(db)
+	groupRepo := _groupRepo.NewBookingGroupRepository(db)
+	reportRepo := _reportRepo.NewReportRepository(db)
+	registrationRepo := _registrationRepo.NewRegistrationRepository(db)
 
+	ratePlanSer := _ratePlanSer.NewRatePlanService(ratePlanRepo)
 	customerSer := _customerSer.NewCustomerService(customerRepo)
-	historySer := _historySer.NewHistoryService(historyRepo)
+	historySer := _historySer.NewHistoryService(historyRepo, ratePlanSer)
 	userSer := _userSer.NewUserService(userRepo)
 	citizenshipSer := _citizenshipSer.NewCitizenshipService(citizenshipRepo)
+	retentionSer := _retentionSer.NewRetentionService(retentionRepo, customerSer)
+	roomSer := _roomSer.NewRoomService(roomRepo, ratePlanSer)
+	reservationSer := _reservationSer.NewReservationService(reservationRepo, ratePlanSer)
+	paymentSer := _paymentSer.NewPaymentService(paymentRepo)
+	invoiceSer := _invoiceSer.NewInvoiceService(invoiceRepo)
+	stayGuestSer := _stayGuestSer.NewStayGuestService(stayGuestRepo, ratePlanSer)
+	cancellationSer := _cancellationSer.NewCancellationPolicyService(cancellationRepo)
+	groupSer := _groupSer.NewBookingGroupService(groupRepo, reservationSer)
+	reportSer := _reportSer.NewReportService(reportRepo)
+	registrationSer := _registrationSer.NewRegistrationService(registrationRepo)
 
 	_customerHandlerHttpDelivery.NewCustomerHandler(router, customerSer)
 	_historyHandlerHttpDelivery.NewHistoryHandler(router, historySer)
 	_citizenshipHandlerHttpDelivery.NewCitizenshipHandler(router, citizenshipSer)
 	_userHandlerHttpDelivery.NewUserHandler(router, userSer)
+	_retentionHandlerHttpDelivery.NewRetentionHandler(router, retentionSer)
+	_roomHandlerHttpDelivery.NewRoomHandler(router, roomSer)
+	_reservationHandlerHttpDelivery.NewReservationHandler(router, reservationSer)
+	_ratePlanHandlerHttpDelivery.NewRatePlanHandler(router, ratePlanSer)
+	_paymentHandlerHttpDelivery.NewPaymentHandler(router, paymentSer)
+	_invoiceHandlerHttpDelivery.NewInvoiceHandler(router, invoiceSer)
+	_stayGuestHandlerHttpDelivery.NewStayGuestHandler(router, stayGuestSer)
+	_cancellationHandlerHttpDelivery.NewCancellationPolicyHandler(router, cancellationSer)
+	_groupHandlerHttpDelivery.NewBookingGroupHandler(router, groupSer)
+	_reportHandlerHttpDelivery.NewReportHandler(router, reportSer)
+	_registrationHandlerHttpDelivery.NewRegistrationHandler(router, registrationSer)
 
 	route.NewRoute(router)
 
@@ -128,6 +248,26 @@ func main() {
 		Handler: router,
 	}
 
+	if config.Val.RetentionConfig != nil && config.Val.RetentionConfig.Enabled {
+		interval := time.Duration(config.Val.RetentionConfig.IntervalHours) * time.Hour
+		if interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		// One pass runs at startup, so a server restarted more often than the interval still purges
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				if _, err := retentionSer.ApplyRetentionRules(); err != nil {
+					log.Println("Retention job failed:", err)
+				} else {
+					log.Println("Retention job finished")
+				}
+				<-ticker.C
+			}
+		}()
+	}
+
 	log.Println("Server is running")
 	go func() {
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -135,7 +275,7 @@ func main() {
 		}
 	}()
 
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 	log.Println("Shutdown Server ...")
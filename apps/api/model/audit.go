@@ -0,0 +1,15 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type AuditLog struct {
+	Id        uuid.UUID `json:"Id"        gorm:"primary_key; column:Id; not null; type:char(36);"`
+	Entity    string    `json:"Entity"    gorm:"column:Entity; not null; type:varchar(50); index:idx_audit_entity"`
+	EntityId  string    `json:"EntityId"  gorm:"column:EntityId; not null; type:varchar(36); index:idx_audit_entity"`
+	Action    string    `json:"Action"    gorm:"column:Action; not null; type:varchar(50)"`
+	Detail    string    `json:"Detail"    gorm:"column:Detail"`
+	CreatedAt time.Time `json:"CreatedAt" gorm:"column:CreatedAt; not null; index"`
+}
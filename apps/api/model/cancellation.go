@@ -0,0 +1,33 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// CancellationPolicy sets the fee charged when a reservation is cancelled or its guest does not show up. Fees are
+// a percent of the reservation price.
+type CancellationPolicy struct {
+	Id        uuid.UUID `json:"Id"        gorm:"primary_key; column:Id; not null; type:char(36);"`
+	Name      string    `json:"Name"      gorm:"column:Name; not null; type:varchar(100); uniqueIndex"`
+	FreeDays  int       `json:"FreeDays"  gorm:"column:FreeDays; not null"`                 // Cancelling at least this many days before check-in is free
+	LateFee   float64   `json:"LateFee"   gorm:"column:LateFee; not null"`                  // Percent charged for a later cancellation
+	NoShowFee float64   `json:"NoShowFee" gorm:"column:NoShowFee; not null"`                // Percent charged when the guest does not show up
+	IsDefault bool      `json:"IsDefault" gorm:"column:IsDefault; not null; default:false"` // Given to reservations booked without a policy
+	Active    bool      `json:"Active"    gorm:"column:Active; not null"`
+	Note      string    `json:"Note"      gorm:"column:Note"`
+}
+
+// FeePercent returns the percent of the price charged for a reservation checking in on checkIn that is cancelled
+// on date, or whose guest does not show up when noShow is set
+func (p *CancellationPolicy) FeePercent(checkIn time.Time, date time.Time, noShow bool) float64 {
+	if noShow {
+		return p.NoShowFee
+	}
+	in := time.Date(checkIn.Year(), checkIn.Month(), checkIn.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if int(in.Sub(day).Hours()/24) >= p.FreeDays {
+		return 0
+	}
+	return p.LateFee
+}
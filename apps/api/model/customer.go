@@ -17,5 +17,7 @@ type Customer struct {
 	CitizenshipId int         `json:"CitizenshipId" gorm:"column:CitizenshipId; not null"`
 	Citizenship   Citizenship `                     gorm:"foreignKey:CitizenshipId; references:Id"`
 	Note          string      `json:"Note"          gorm:"column:Note"`
+	AnonymizedAt  *time.Time  `json:"AnonymizedAt"  gorm:"column:AnonymizedAt"`
+	CreatedAt     time.Time   `json:"CreatedAt"     gorm:"column:CreatedAt; not null"`
 	Histories     []History   `                     gorm:"foreignKey:CustomerId; references:Id"`
 }
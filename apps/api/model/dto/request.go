@@ -37,15 +37,57 @@ type CustomerPhoneRequest struct {
 	PhoneNumber string `json:"PhoneNumber"`
 }
 
+type CustomerFilter struct {
+	Name        string `json:"Name"`
+	PhoneNumber string `json:"PhoneNumber"`
+	NationalId  string `json:"NationalId"`
+	Citizenship int    `json:"Citizenship"`
+}
+
+type CustomerExportRequest struct {
+	ExportRequest
+	CustomerFilter
+}
+
+type CustomerVCardRequest struct {
+	CustomerId uuid.UUID `json:"CustomerId"`
+	Version    string    `json:"Version"` // 3.0 or 4.0
+}
+
+type CustomerListVCardRequest struct {
+	CustomerFilter
+	Version string `json:"Version"` // 3.0 or 4.0
+}
+
+type CustomerImportRow struct {
+	Row         int             `json:"Row"`
+	Customer    CustomerRequest `json:"Customer"`
+	Citizenship string          `json:"Citizenship"` // Citizenship Id, Nation or Alpha3
+}
+
+type CustomerEraseRequest struct {
+	CustomerId uuid.UUID `json:"CustomerId"`
+	Reason     string    `json:"Reason"`
+}
+
 // History Request
 
 type HistoryRequest struct {
 	HistoryId      uuid.UUID `json:"HistoryId"`
 	CustomerId     uuid.UUID `json:"CustomerId"`
-	Date           string    `json:"Date"`
+	CheckIn        string    `json:"CheckIn"`
+	CheckOut       string    `json:"CheckOut"`
+	Date           string    `json:"Date"` // Deprecated: a one-night stay starting on Date, used when CheckIn is empty
 	NumberOfPeople int       `json:"NumberOfPeople"`
-	Price          int       `json:"Price"`
-	Room           string    `json:"Room"`
+	Price          int       `json:"Price"`       // Deprecated: room charge in the major unit for creations with RoomCharge 0, ignored on update since responses carry the total as Price
+	Currency       string    `json:"Currency"`    // ISO 4217 code, empty for the billing currency
+	RoomCharge     int64     `json:"RoomCharge"`  // Minor unit of Currency, 0 takes the price quoted by the rate plan, as does a quoted charge sent back unchanged on update
+	ExtraCharge    int64     `json:"ExtraCharge"` // Minor unit of Currency
+	Discount       int64     `json:"Discount"`    // Minor unit of Currency
+	RatePlanId     uuid.UUID `json:"RatePlanId"`  // Empty with RoomCharge 0 picks the cheapest active plan of the room type, or keeps the current one on update
+	RoomId         uuid.UUID `json:"RoomId"`
+	Room           string    `json:"Room"`    // Room number, used when RoomId is empty
+	GroupId        uuid.UUID `json:"GroupId"` // Booking group, empty for none or to keep the current one on update
 	Note           string    `json:"Note"`
 }
 
@@ -53,6 +95,13 @@ type HistoryIdRequest struct {
 	HistoryId uuid.UUID `json:"HistoryId"`
 }
 
+type HistoryMoveRequest struct {
+	HistoryId uuid.UUID `json:"HistoryId"`
+	RoomId    uuid.UUID `json:"RoomId"`
+	Room      string    `json:"Room"` // Room number, used when RoomId is empty
+	Date      string    `json:"Date"` // First night in the new room
+}
+
 type DuringRequest struct {
 	StartDate string `json:"startDate"`
 	EndDate   string `json:"endDate"`
@@ -66,6 +115,245 @@ type HistoryCustomerIdRequest struct {
 	CustomerId uuid.UUID `json:"CustomerId"`
 }
 
+type HistoryFilter struct {
+	CustomerId uuid.UUID `json:"CustomerId"`
+	StartDate  string    `json:"startDate"`
+	EndDate    string    `json:"endDate"`
+	Room       string    `json:"Room"`
+}
+
+type HistoryExportRequest struct {
+	ExportRequest
+	HistoryFilter
+}
+
+// Export Request
+
+type ExportRequest struct {
+	Format   string   `json:"Format"`   // csv, xlsx or jsonl
+	Columns  []string `json:"Columns"`  // empty means every column
+	Language string   `json:"Language"` // en or zh-TW
+}
+
+// Room Request
+
+type RoomRequest struct {
+	RoomId   uuid.UUID `json:"RoomId"`
+	Number   string    `json:"Number"`
+	Type     string    `json:"Type"`
+	Capacity int       `json:"Capacity"`
+	BaseRate int       `json:"BaseRate"`
+	Active   bool      `json:"Active"`
+}
+
+type RoomIdRequest struct {
+	RoomId uuid.UUID `json:"RoomId"`
+}
+
+type RoomNumberRequest struct {
+	Number string `json:"Number"`
+}
+
+type RoomOccupancyRequest struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+type RoomAvailabilityRequest struct {
+	CheckIn        string `json:"CheckIn"`
+	CheckOut       string `json:"CheckOut"`
+	NumberOfPeople int    `json:"NumberOfPeople"`
+}
+
+type RoomOutageRequest struct {
+	RoomId    uuid.UUID `json:"RoomId"`
+	StartDate string    `json:"StartDate"` // First night out of service
+	EndDate   string    `json:"EndDate"`   // Last night out of service
+	Reason    string    `json:"Reason"`
+}
+
+type RoomOutageIdRequest struct {
+	OutageId uuid.UUID `json:"OutageId"`
+}
+
+// Reservation Request
+
+type ReservationRequest struct {
+	ReservationId  uuid.UUID `json:"ReservationId"`
+	CustomerId     uuid.UUID `json:"CustomerId"`
+	RoomId         uuid.UUID `json:"RoomId"`
+	CheckIn        string    `json:"CheckIn"`
+	CheckOut       string    `json:"CheckOut"`
+	NumberOfPeople int       `json:"NumberOfPeople"`
+	Price          int       `json:"Price"`    // Agreed price of the stay in the major unit, 0 lets the rate plan price it at check-in
+	Status         string    `json:"Status"`   // tentative or confirmed, only used on create
+	PolicyId       uuid.UUID `json:"PolicyId"` // Cancellation policy, empty for the default one
+	GroupId        uuid.UUID `json:"GroupId"`  // Booking group, empty for none or to keep the current one on update
+	Note           string    `json:"Note"`
+}
+
+type ReservationIdRequest struct {
+	ReservationId uuid.UUID `json:"ReservationId"`
+}
+
+type ReservationCustomerIdRequest struct {
+	CustomerId uuid.UUID `json:"CustomerId"`
+}
+
+type ReservationStatusRequest struct {
+	ReservationId uuid.UUID `json:"ReservationId"`
+	Status        string    `json:"Status"` // confirmed, cancelled or no-show
+}
+
+// Rate Plan Request
+
+type RatePlanSeasonRequest struct {
+	Name            string `json:"Name"`
+	StartDate       string `json:"StartDate"`
+	EndDate         string `json:"EndDate"` // Last night of the season
+	WeekdayRate     int    `json:"WeekdayRate"`
+	WeekendRate     int    `json:"WeekendRate"`
+	ExtraPersonRate int    `json:"ExtraPersonRate"`
+}
+
+type RatePlanRequest struct {
+	RatePlanId      uuid.UUID                `json:"RatePlanId"`
+	Name            string                   `json:"Name"`
+	RoomType        string                   `json:"RoomType"`
+	BaseOccupancy   int                      `json:"BaseOccupancy"`
+	WeekdayRate     int                      `json:"WeekdayRate"`
+	WeekendRate     int                      `json:"WeekendRate"`
+	ExtraPersonRate int                      `json:"ExtraPersonRate"`
+	Active          bool                     `json:"Active"`
+	Note            string                   `json:"Note"`
+	Seasons         []*RatePlanSeasonRequest `json:"Seasons"`
+}
+
+type RatePlanIdRequest struct {
+	RatePlanId uuid.UUID `json:"RatePlanId"`
+}
+
+type RatePlanQuoteRequest struct {
+	RatePlanId     uuid.UUID `json:"RatePlanId"` // Empty picks the cheapest active plan of the room type
+	RoomId         uuid.UUID `json:"RoomId"`
+	Room           string    `json:"Room"` // Room number, used when RoomId is empty
+	CheckIn        string    `json:"CheckIn"`
+	CheckOut       string    `json:"CheckOut"`
+	NumberOfPeople int       `json:"NumberOfPeople"`
+}
+
+// Cancellation Policy Request
+
+type CancellationPolicyRequest struct {
+	PolicyId  uuid.UUID `json:"PolicyId"`
+	Name      string    `json:"Name"`
+	FreeDays  int       `json:"FreeDays"`  // Cancelling at least this many days before check-in is free
+	LateFee   float64   `json:"LateFee"`   // Percent of the reservation price
+	NoShowFee float64   `json:"NoShowFee"` // Percent of the reservation price
+	IsDefault bool      `json:"IsDefault"`
+	Active    bool      `json:"Active"`
+	Note      string    `json:"Note"`
+}
+
+type CancellationPolicyIdRequest struct {
+	PolicyId uuid.UUID `json:"PolicyId"`
+}
+
+// Payment Request
+
+type PaymentRequest struct {
+	HistoryId     uuid.UUID `json:"HistoryId"`     // Stay paid for
+	ReservationId uuid.UUID `json:"ReservationId"` // Reservation a deposit is taken for, used when HistoryId is empty
+	Kind          string    `json:"Kind"`          // payment or refund
+	Method        string    `json:"Method"`        // cash, card, transfer, mobile or other
+	Amount        int64     `json:"Amount"`        // Minor unit of Currency
+	Currency      string    `json:"Currency"`      // Empty for the currency of the stay
+	Reference     string    `json:"Reference"`
+	ReceivedBy    string    `json:"ReceivedBy"`
+	PaidAt        string    `json:"PaidAt"` // "2006-01-02 15:04" or "2006-01-02", empty for now
+	Note          string    `json:"Note"`
+}
+
+type PaymentHistoryIdRequest struct {
+	HistoryId uuid.UUID `json:"HistoryId"`
+}
+
+type PaymentReservationIdRequest struct {
+	ReservationId uuid.UUID `json:"ReservationId"`
+}
+
+type PaymentCustomerIdRequest struct {
+	CustomerId uuid.UUID `json:"CustomerId"`
+}
+
+type StayBalanceFilter struct {
+	HistoryId  uuid.UUID
+	CustomerId uuid.UUID
+	UnpaidOnly bool
+}
+
+// Invoice Request
+
+type InvoiceRequest struct {
+	HistoryId uuid.UUID `json:"HistoryId"`
+	Note      string    `json:"Note"`
+}
+
+type InvoiceGroupRequest struct {
+	GroupId uuid.UUID `json:"GroupId"`
+	Note    string    `json:"Note"`
+}
+
+type InvoiceGroupIdRequest struct {
+	GroupId uuid.UUID `json:"GroupId"`
+}
+
+type InvoiceIdRequest struct {
+	InvoiceId uuid.UUID `json:"InvoiceId"`
+}
+
+type InvoiceHistoryIdRequest struct {
+	HistoryId uuid.UUID `json:"HistoryId"`
+}
+
+type InvoiceVoidRequest struct {
+	InvoiceId uuid.UUID `json:"InvoiceId"`
+	Reason    string    `json:"Reason"`
+}
+
+// Booking Group Request
+
+type GroupRequest struct {
+	GroupId     uuid.UUID `json:"GroupId"`
+	Name        string    `json:"Name"`
+	OrganizerId uuid.UUID `json:"OrganizerId"`
+	Note        string    `json:"Note"`
+}
+
+type GroupIdRequest struct {
+	GroupId uuid.UUID `json:"GroupId"`
+}
+
+type GroupMoveRequest struct {
+	GroupId uuid.UUID `json:"GroupId"`
+	CheckIn string    `json:"CheckIn"` // New check-in date of the earliest reservation, the others keep their offset
+}
+
+// Stay Guest Request
+
+type StayGuestRequest struct {
+	HistoryId  uuid.UUID `json:"HistoryId"`
+	CustomerId uuid.UUID `json:"CustomerId"`
+}
+
+type StayGuestHistoryIdRequest struct {
+	HistoryId uuid.UUID `json:"HistoryId"`
+}
+
+type StayGuestCustomerIdRequest struct {
+	CustomerId uuid.UUID `json:"CustomerId"`
+}
+
 // Citizenship Request
 
 type CitizenshipRequest struct {
@@ -76,6 +364,93 @@ type CitizenshipNameRequest struct {
 	CitizenshipName string `json:"CitizenshipName"`
 }
 
+// Retention Request
+
+type RetentionRuleRequest struct {
+	RuleId        uuid.UUID `json:"RuleId"`
+	Entity        string    `json:"Entity"`
+	CitizenshipId int       `json:"CitizenshipId"`
+	Years         int       `json:"Years"`
+	Action        string    `json:"Action"`
+	Active        bool      `json:"Active"`
+	Note          string    `json:"Note"`
+}
+
+type RetentionRuleIdRequest struct {
+	RuleId uuid.UUID `json:"RuleId"`
+}
+
+// Report Request
+
+type RevenueReportRequest struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`  // Included
+	Period    string `json:"Period"`   // day, month or year
+	Currency  string `json:"Currency"` // Empty for the billing currency
+}
+
+type KpiReportRequest struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`  // Included, at most a year after startDate
+	RoomType  string `json:"RoomType"` // Empty for every room type
+	Currency  string `json:"Currency"` // Empty for the billing currency
+}
+
+type NationalityReportRequest struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"` // Included
+}
+
+type NationalityExportRequest struct {
+	NationalityReportRequest
+	Language string `json:"Language"` // en or zh-TW
+}
+
+type CustomerValueRequest struct {
+	CustomerId uuid.UUID `json:"CustomerId"`
+	Currency   string    `json:"Currency"` // Empty for the billing currency
+}
+
+type TopGuestsRequest struct {
+	StartDate string `json:"startDate"` // Both dates empty for every stay
+	EndDate   string `json:"endDate"`   // Included
+	OrderBy   string `json:"OrderBy"`   // spend, stays or nights, spend when empty
+	Limit     int    `json:"Limit"`     // 10 when 0, at most 100
+	Currency  string `json:"Currency"`  // Empty for the billing currency
+}
+
+type RepeatRateRequest struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"` // Included
+	Period    string `json:"Period"`  // day, month or year
+}
+
+type ForecastRequest struct {
+	Days int `json:"Days"` // Nights from today, 90 when 0, at most 366
+}
+
+// Registration Request
+
+type RegistrationRequest struct {
+	Date        string `json:"Date"`        // Night whose foreign guests are reported
+	Unsubmitted bool   `json:"Unsubmitted"` // Leave out the guests already reported
+}
+
+type RegistrationExportRequest struct {
+	Date     string `json:"Date"`
+	Resubmit bool   `json:"Resubmit"` // Include the guests already reported
+}
+
+type RegistrationGuest struct {
+	HistoryId  uuid.UUID `json:"HistoryId"`
+	CustomerId uuid.UUID `json:"CustomerId"`
+}
+
+type RegistrationSubmitRequest struct {
+	Date   string              `json:"Date"`
+	Guests []RegistrationGuest `json:"Guests"` // Guests of the file sent to the authorities
+}
+
 // User Request
 
 type UserLoginRequest struct {
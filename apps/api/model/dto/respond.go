@@ -1,6 +1,326 @@
 package dto
 
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+	"time"
+)
+
 type Response struct {
 	ProcessCode    string `json:"Process Code"`
 	ProcessMessage string `json:"Process Message"`
 }
+
+// Customer Respond
+
+type CustomerContacts struct {
+	Address     string `json:"Address"`
+	PhoneNumber string `json:"PhoneNumber"`
+	CarNumber   string `json:"CarNumber"`
+}
+
+type CustomerDataPackage struct {
+	GeneratedAt             time.Time                       `json:"GeneratedAt"`
+	Profile                 *model.Customer                 `json:"Profile"`
+	Contacts                CustomerContacts                `json:"Contacts"`
+	Stays                   []model.History                 `json:"Stays"`
+	CompanionStays          []*model.History                `json:"CompanionStays"` // Stays of other customers the customer was a guest of
+	Reservations            []*model.Reservation            `json:"Reservations"`
+	Payments                []*model.Payment                `json:"Payments"`
+	Invoices                []*model.Invoice                `json:"Invoices"`
+	RegistrationSubmissions []*model.RegistrationSubmission `json:"RegistrationSubmissions"`
+	AuditTrail              []*model.AuditLog               `json:"AuditTrail"`
+}
+
+type CustomerImportError struct {
+	Row        int    `json:"Row"`
+	NationalId string `json:"NationalId"`
+	Message    string `json:"Message"`
+}
+
+type CustomerImportResult struct {
+	DryRun  bool                   `json:"DryRun"`
+	Total   int                    `json:"Total"`
+	Created int                    `json:"Created"`
+	Updated int                    `json:"Updated"`
+	Failed  int                    `json:"Failed"`
+	Errors  []*CustomerImportError `json:"Errors"`
+}
+
+// Room Respond
+
+type RoomAvailability struct {
+	Room     *model.Room     `json:"Room"`
+	Nights   int             `json:"Nights"`
+	RatePlan *model.RatePlan `json:"RatePlan"` // Plan quoting the stay, null when the room type has none
+	Rate     int             `json:"Rate"`     // Average price of one night
+	Total    int             `json:"Total"`    // Price of the whole stay, the BaseRate of every night without a rate plan
+}
+
+type OccupancyStay struct {
+	Id             uuid.UUID `json:"Id"             gorm:"column:Id"`
+	Source         string    `json:"Source"         gorm:"column:Source"` // history or reservation
+	RoomId         uuid.UUID `json:"-"              gorm:"column:RoomId"`
+	CustomerId     uuid.UUID `json:"CustomerId"     gorm:"column:CustomerId"`
+	GuestName      string    `json:"GuestName"      gorm:"column:GuestName"`
+	NumberOfPeople int       `json:"NumberOfPeople" gorm:"column:NumberOfPeople"`
+	Status         string    `json:"Status"         gorm:"column:Status"`
+	CheckIn        time.Time `json:"CheckIn"        gorm:"column:CheckIn"`
+	CheckOut       time.Time `json:"CheckOut"       gorm:"column:CheckOut"`
+}
+
+type RoomOccupancy struct {
+	Room   *model.Room      `json:"Room"`
+	Nights []*OccupancyStay `json:"Nights"` // One entry per date of the calendar, null when the room is empty that night
+}
+
+type OccupancyCalendar struct {
+	StartDate string           `json:"startDate"`
+	EndDate   string           `json:"endDate"`
+	Dates     []string         `json:"Dates"`
+	Rooms     []*RoomOccupancy `json:"Rooms"`
+}
+
+// Rate Plan Respond
+
+type QuoteNight struct {
+	Date        string `json:"Date"`
+	Season      string `json:"Season"` // Empty when the plan rates apply
+	Weekend     bool   `json:"Weekend"`
+	Rate        int    `json:"Rate"`
+	ExtraPeople int    `json:"ExtraPeople"`
+	ExtraCharge int    `json:"ExtraCharge"`
+	Amount      int    `json:"Amount"`
+}
+
+type StayQuote struct {
+	RatePlan       *model.RatePlan `json:"RatePlan"`
+	Room           *model.Room     `json:"Room"`
+	CheckIn        string          `json:"CheckIn"`
+	CheckOut       string          `json:"CheckOut"`
+	NumberOfPeople int             `json:"NumberOfPeople"`
+	Nights         []*QuoteNight   `json:"Nights"`
+	Total          int             `json:"Total"`
+}
+
+// Payment Respond
+
+type StayBalance struct {
+	HistoryId    uuid.UUID `json:"HistoryId"    gorm:"column:HistoryId"`
+	CustomerId   uuid.UUID `json:"CustomerId"   gorm:"column:CustomerId"`
+	CustomerName string    `json:"CustomerName" gorm:"column:CustomerName"`
+	CheckIn      time.Time `json:"CheckIn"      gorm:"column:CheckIn"`
+	CheckOut     time.Time `json:"CheckOut"     gorm:"column:CheckOut"`
+	Currency     string    `json:"Currency"     gorm:"column:Currency"`
+	Total        int64     `json:"Total"        gorm:"column:Total"` // Amounts are in the minor unit of Currency
+	Paid         int64     `json:"Paid"         gorm:"column:Paid"`
+	Refunded     int64     `json:"Refunded"     gorm:"column:Refunded"`
+	Balance      int64     `json:"Balance"      gorm:"-"`
+	Status       string    `json:"Status"       gorm:"-"` // unpaid, partial, paid or overpaid
+}
+
+type CustomerBalance struct {
+	CustomerId uuid.UUID      `json:"CustomerId"`
+	Balances   []model.Money  `json:"Balances"` // Outstanding amount per currency
+	Stays      []*StayBalance `json:"Stays"`
+}
+
+// Booking Group Respond
+
+type GroupStays struct {
+	Group        *model.BookingGroup  `json:"Group"`
+	Reservations []*model.Reservation `json:"Reservations"`
+	Histories    []*model.History     `json:"Histories"`
+}
+
+type GroupOperationError struct {
+	ReservationId uuid.UUID `json:"ReservationId"`
+	Room          string    `json:"Room"`
+	Message       string    `json:"Message"`
+}
+
+type GroupOperationResult struct {
+	Succeeded []*model.Reservation   `json:"Succeeded"`
+	Failed    []*GroupOperationError `json:"Failed"` // Reservations left as they were
+}
+
+// Stay Guest Respond
+
+type TravelCompanion struct {
+	Customer    *model.Customer `json:"Customer"`
+	Stays       int             `json:"Stays"`       // Stays shared with the customer
+	LastCheckIn time.Time       `json:"LastCheckIn"` // Check-in of the latest shared stay
+}
+
+// Retention Respond
+
+type RetentionRuleReport struct {
+	Rule        *model.RetentionRule `json:"Rule"`
+	Cutoff      time.Time            `json:"Cutoff"`
+	CustomerIds []uuid.UUID          `json:"CustomerIds"`
+	HistoryIds  []uuid.UUID          `json:"HistoryIds"`
+}
+
+type RetentionReport struct {
+	DryRun      bool                   `json:"DryRun"`
+	GeneratedAt time.Time              `json:"GeneratedAt"`
+	Rules       []*RetentionRuleReport `json:"Rules"`
+}
+
+// Report Respond
+
+type RevenueFigures struct {
+	Revenue    int64 `json:"Revenue"    gorm:"column:Revenue"`    // Amounts are in the minor unit of the report Currency, taxes included
+	NetRevenue int64 `json:"NetRevenue" gorm:"column:NetRevenue"` // Revenue without taxes
+	Stays      int   `json:"Stays"      gorm:"column:Stays"`      // Fees are revenue but not stays
+	Guests     int   `json:"Guests"     gorm:"column:Guests"`     // Sum of NumberOfPeople of the stays
+}
+
+type RevenuePeriod struct {
+	Period string `json:"Period" gorm:"column:Period"` // 2006-01-02, 2006-01 or 2006
+	RevenueFigures
+	Previous      RevenueFigures `json:"Previous"      gorm:"-"` // Figures of the period before
+	RevenueChange float64        `json:"RevenueChange" gorm:"-"` // Percent change of Revenue from Previous, 0 when Previous has none
+}
+
+type RevenueBreakdown struct {
+	Name string `json:"Name" gorm:"column:Name"` // Room number or Nation
+	RevenueFigures
+}
+
+type RevenueCurrency struct {
+	Currency string `json:"Currency" gorm:"column:Currency"`
+	RevenueFigures
+}
+
+type RevenueReport struct {
+	StartDate     string              `json:"startDate"` // Widened to whole periods
+	EndDate       string              `json:"endDate"`
+	Period        string              `json:"Period"`
+	Currency      string              `json:"Currency"`
+	Total         RevenueFigures      `json:"Total"`
+	Previous      RevenueFigures      `json:"Previous"` // Figures of as many periods right before startDate
+	RevenueChange float64             `json:"RevenueChange"`
+	Periods       []*RevenuePeriod    `json:"Periods"` // Every period of the range, empty ones included
+	ByRoom        []*RevenueBreakdown `json:"ByRoom"`
+	ByCitizenship []*RevenueBreakdown `json:"ByCitizenship"`
+	ByCurrency    []*RevenueCurrency  `json:"ByCurrency"` // Figures of the range in other currencies, left out of the rest of the report
+}
+
+type KpiFigures struct {
+	Available   int     `json:"Available"   gorm:"column:Available"`   // Room-nights for sale: active rooms not out of service
+	Occupied    int     `json:"Occupied"    gorm:"column:Occupied"`    // Room-nights sold
+	RoomRevenue int64   `json:"RoomRevenue" gorm:"column:RoomRevenue"` // Room charges spread evenly over the nights of each stay, minor unit of Currency
+	Occupancy   float64 `json:"Occupancy"   gorm:"-"`                  // Percent of Available that is Occupied
+	ADR         int64   `json:"ADR"         gorm:"-"`                  // Average daily rate: RoomRevenue per Occupied night
+	RevPAR      int64   `json:"RevPAR"      gorm:"-"`                  // Revenue per available room: RoomRevenue per Available night
+}
+
+type KpiDay struct {
+	Date string `json:"Date" gorm:"column:Date"`
+	KpiFigures
+}
+
+type KpiReport struct {
+	StartDate string     `json:"startDate"`
+	EndDate   string     `json:"endDate"`
+	RoomType  string     `json:"RoomType"`
+	Currency  string     `json:"Currency"`
+	Total     KpiFigures `json:"Total"`
+	Days      []*KpiDay  `json:"Days"` // One entry per night of the range
+}
+
+type NationalityFigures struct {
+	Guests      int `json:"Guests"      gorm:"column:Guests"`      // Unique guests, primary guests and companions, staying a night of the range
+	GuestNights int `json:"GuestNights" gorm:"column:GuestNights"` // Nights of those guests within the range
+}
+
+type NationalityRow struct {
+	Nation string `json:"Nation" gorm:"column:Nation"`
+	Alpha3 string `json:"Alpha3" gorm:"column:Alpha3"`
+	NationalityFigures
+}
+
+type NationalityReport struct {
+	StartDate string             `json:"startDate"`
+	EndDate   string             `json:"endDate"`
+	Total     NationalityFigures `json:"Total"`
+	Rows      []*NationalityRow  `json:"Rows"` // Only citizenships with guests
+}
+
+type CustomerValue struct {
+	CustomerId   uuid.UUID `json:"CustomerId"   gorm:"column:CustomerId"`
+	Name         string    `json:"Name"         gorm:"column:Name"`
+	Currency     string    `json:"Currency"     gorm:"-"`
+	Stays        int       `json:"Stays"        gorm:"column:Stays"` // Fees are spend but not stays
+	Nights       int       `json:"Nights"       gorm:"column:Nights"`
+	Spend        int64     `json:"Spend"        gorm:"column:Spend"`     // TotalAmount of the Histories in Currency, minor unit
+	AverageSpend int64     `json:"AverageSpend" gorm:"-"`                // Spend per stay
+	FirstStay    string    `json:"FirstStay"    gorm:"column:FirstStay"` // Check-in of the first stay, empty without stays
+	LastStay     string    `json:"LastStay"     gorm:"column:LastStay"`  // Check-in of the last stay
+}
+
+type TopGuests struct {
+	StartDate string           `json:"startDate"`
+	EndDate   string           `json:"endDate"`
+	OrderBy   string           `json:"OrderBy"`
+	Currency  string           `json:"Currency"`
+	Guests    []*CustomerValue `json:"Guests"` // Figures of the stays within the range only
+}
+
+type RepeatRateFigures struct {
+	Guests       int     `json:"Guests"       gorm:"column:Guests"`       // Customers checking in within the period
+	RepeatGuests int     `json:"RepeatGuests" gorm:"column:RepeatGuests"` // Of Guests, those with two stays or more by the end of the period
+	RepeatRate   float64 `json:"RepeatRate"   gorm:"-"`                   // Percent of Guests that are RepeatGuests
+}
+
+type RepeatRatePeriod struct {
+	Period string `json:"Period" gorm:"column:Period"` // 2006-01-02, 2006-01 or 2006
+	RepeatRateFigures
+}
+
+type RepeatRateReport struct {
+	StartDate string              `json:"startDate"` // Widened to whole periods
+	EndDate   string              `json:"endDate"`
+	Period    string              `json:"Period"`
+	Total     RepeatRateFigures   `json:"Total"` // A guest counts once over the whole range
+	Periods   []*RepeatRatePeriod `json:"Periods"`
+}
+
+type ForecastFigures struct {
+	Rooms     int     `json:"Rooms"     gorm:"column:Rooms"`
+	Revenue   int64   `json:"Revenue"   gorm:"column:Revenue"` // Room revenue in the minor unit of the billing currency
+	Occupancy float64 `json:"Occupancy" gorm:"-"`              // Percent of Available
+}
+
+type ForecastDay struct {
+	Date       string          `json:"Date"`
+	Available  int             `json:"Available"`  // Active rooms not out of service
+	OnTheBooks ForecastFigures `json:"OnTheBooks"` // Stays in house and confirmed reservations
+	Pickup     ForecastFigures `json:"Pickup"`     // Booked last year after the same lead time, up to the rooms left
+	Projected  ForecastFigures `json:"Projected"`  // OnTheBooks and Pickup
+}
+
+type Forecast struct {
+	StartDate string         `json:"startDate"` // Today
+	EndDate   string         `json:"endDate"`
+	Currency  string         `json:"Currency"`
+	Total     ForecastDay    `json:"Total"` // Without Date
+	Days      []*ForecastDay `json:"Days"`
+}
+
+type RegistrationRow struct {
+	HistoryId      uuid.UUID  `json:"HistoryId"      gorm:"column:HistoryId"`
+	CustomerId     uuid.UUID  `json:"CustomerId"     gorm:"column:CustomerId"`
+	Name           string     `json:"Name"           gorm:"column:Name"`
+	PassportNumber string     `json:"PassportNumber" gorm:"column:PassportNumber"` // NationalId of the customer
+	Nationality    string     `json:"Nationality"    gorm:"column:Nationality"`    // Alpha3
+	Nation         string     `json:"Nation"         gorm:"column:Nation"`
+	Birthday       time.Time  `json:"Birthday"       gorm:"column:Birthday"`
+	Gender         string     `json:"Gender"         gorm:"column:Gender"`
+	CheckIn        time.Time  `json:"CheckIn"        gorm:"column:CheckIn"`
+	CheckOut       time.Time  `json:"CheckOut"       gorm:"column:CheckOut"`
+	Room           string     `json:"Room"           gorm:"column:Room"` // Room of the guest that night
+	SubmittedAt    *time.Time `json:"SubmittedAt"    gorm:"column:SubmittedAt"`
+	Missing        []string   `json:"Missing"        gorm:"-"` // Required fields left empty, the guest cannot be reported until they are filled
+}
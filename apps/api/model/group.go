@@ -0,0 +1,18 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// BookingGroup gathers the reservations and stays of several rooms booked together, such as a tour group, under
+// the customer who organised them
+type BookingGroup struct {
+	Id          uuid.UUID `json:"Id"          gorm:"primary_key; column:Id; not null; type:char(36);"`
+	Name        string    `json:"Name"        gorm:"column:Name; not null; type:varchar(100)"`
+	OrganizerId uuid.UUID `json:"OrganizerId" gorm:"column:OrganizerId; not null; type:char(36); index"`
+	Organizer   Customer  `                   gorm:"foreignKey:OrganizerId; references:Id"`
+	Note        string    `json:"Note"        gorm:"column:Note"`
+	CreatedAt   time.Time `json:"CreatedAt"   gorm:"column:CreatedAt; not null"`
+	UpdatedAt   time.Time `json:"UpdatedAt"   gorm:"column:UpdatedAt; not null"`
+}
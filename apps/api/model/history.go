@@ -1,16 +1,107 @@
 package model
 
 import (
+	"errors"
 	"github.com/google/uuid"
+	"math"
 	"time"
 )
 
+const (
+	HistoryKindStay            = "stay"
+	HistoryKindCancellationFee = "cancellation_fee"
+	HistoryKindNoShowFee       = "no_show_fee"
+)
+
+// History is a stay, or a fee charged for a reservation that never became one. Fees keep the room and dates of the
+// reservation but do not occupy the room.
 type History struct {
-	Id             uuid.UUID `json:"Id"             gorm:"primary_key; column:Id; not null; type:char(36);"`
-	CustomerId     uuid.UUID `json:"CustomerId"     gorm:"column:CustomerId; not null; type:char(36);"`
-	Date           time.Time `json:"Date"           gorm:"column:Date; not null; index"`
-	NumberOfPeople int       `json:"NumberOfPeople" gorm:"column:NumberOfPeople; not null"`
-	Price          int       `json:"Price"          gorm:"column:Price; not null"`
-	Note           string    `json:"Note"           gorm:"column:Note"`
-	Room           string    `json:"Room"           gorm:"column:Room; not null"`
+	Id              uuid.UUID         `json:"Id"              gorm:"primary_key; column:Id; not null; type:char(36);"`
+	CustomerId      uuid.UUID         `json:"CustomerId"      gorm:"column:CustomerId; not null; type:char(36);"`
+	Kind            string            `json:"Kind"            gorm:"column:Kind; not null; type:varchar(20); default:'stay'; index"` // stay, cancellation_fee or no_show_fee
+	CheckIn         time.Time         `json:"CheckIn"         gorm:"column:CheckIn; not null; index"`
+	CheckOut        time.Time         `json:"CheckOut"        gorm:"column:CheckOut; not null; index"`
+	NumberOfPeople  int               `json:"NumberOfPeople"  gorm:"column:NumberOfPeople; not null"`
+	Price           int               `json:"Price"           gorm:"column:Price; not null"` // TotalAmount in the major unit, kept for older clients
+	Currency        string            `json:"Currency"        gorm:"column:Currency; not null; type:char(3)"`
+	RoomCharge      int64             `json:"RoomCharge"      gorm:"column:RoomCharge; not null"` // Amounts are in the minor unit of Currency
+	ExtraCharge     int64             `json:"ExtraCharge"     gorm:"column:ExtraCharge; not null"`
+	Discount        int64             `json:"Discount"        gorm:"column:Discount; not null"`
+	TaxAmount       int64             `json:"TaxAmount"       gorm:"column:TaxAmount; not null"` // Sum of TaxLines, inclusive ones too
+	TotalAmount     int64             `json:"TotalAmount"     gorm:"column:TotalAmount; not null"`
+	TaxLines        []*HistoryTaxLine `json:"TaxLines"        gorm:"foreignKey:HistoryId; references:Id"`
+	Guests          []*StayGuest      `json:"Guests"          gorm:"foreignKey:HistoryId; references:Id"` // Everyone registered in the room, the customer included
+	Segments        []*StaySegment    `json:"Segments"        gorm:"foreignKey:HistoryId; references:Id"` // Rooms of the stay in date order, more than one after a room move
+	RatePlanId      *uuid.UUID        `json:"RatePlanId"      gorm:"column:RatePlanId; type:char(36); index"`
+	GroupId         *uuid.UUID        `json:"GroupId"         gorm:"column:GroupId; type:char(36); index"`            // BookingGroup the stay belongs to
+	PriceOverridden bool              `json:"PriceOverridden" gorm:"column:PriceOverridden; not null; default:false"` // RoomCharge was typed by hand instead of quoted
+	Note            string            `json:"Note"            gorm:"column:Note"`
+	RoomId          uuid.UUID         `json:"RoomId"          gorm:"column:RoomId; not null; type:char(36); index"` // Room of the last segment
+	Room            Room              `                       gorm:"foreignKey:RoomId; references:Id"`
+}
+
+// Nights returns the number of nights between CheckIn and CheckOut
+func (h *History) Nights() int {
+	return NightsBetween(h.CheckIn, h.CheckOut)
+}
+
+// NightsBetween returns the number of nights from the day of checkIn to the day of checkOut, whatever their times
+func NightsBetween(checkIn time.Time, checkOut time.Time) int {
+	in := time.Date(checkIn.Year(), checkIn.Month(), checkIn.Day(), 0, 0, 0, 0, time.UTC)
+	out := time.Date(checkOut.Year(), checkOut.Month(), checkOut.Day(), 0, 0, 0, 0, time.UTC)
+	return int(out.Sub(in).Hours() / 24)
+}
+
+// ComputeTotals prices the stay from RoomCharge, ExtraCharge and Discount: every tax gets a line, inclusive taxes
+// are carved out of the charges and exclusive ones are added to TotalAmount
+func (h *History) ComputeTotals(taxes []TaxRate) error {
+	if h.RoomCharge < 0 || h.ExtraCharge < 0 || h.Discount < 0 {
+		return errors.New("error CRMS : HistoryService Info is incomplete")
+	}
+	if h.Discount > h.RoomCharge+h.ExtraCharge {
+		return errors.New("error CRMS : Discount exceeds the charges")
+	}
+	net := Money{Amount: h.RoomCharge + h.ExtraCharge - h.Discount, Currency: h.Currency}
+	total := net
+	h.TaxLines = nil
+	h.TaxAmount = 0
+	for _, tax := range taxes {
+		amount := net.Tax(tax.BasisPoints(), tax.Inclusive)
+		h.TaxLines = append(h.TaxLines, &HistoryTaxLine{
+			Id:        uuid.New(),
+			HistoryId: h.Id,
+			Name:      tax.Name,
+			Rate:      tax.Rate,
+			Inclusive: tax.Inclusive,
+			Amount:    amount.Amount,
+		})
+		h.TaxAmount += amount.Amount
+		if !tax.Inclusive {
+			total.Amount += amount.Amount
+		}
+	}
+	h.TotalAmount = total.Amount
+	h.Price = total.Major()
+	return nil
+}
+
+type HistoryTaxLine struct {
+	Id        uuid.UUID `json:"Id"        gorm:"primary_key; column:Id; not null; type:char(36);"`
+	HistoryId uuid.UUID `json:"HistoryId" gorm:"column:HistoryId; not null; type:char(36); index"`
+	Name      string    `json:"Name"      gorm:"column:Name; not null; type:varchar(50)"`
+	Rate      float64   `json:"Rate"      gorm:"column:Rate; not null"` // Percent
+	Inclusive bool      `json:"Inclusive" gorm:"column:Inclusive; not null"`
+	Amount    int64     `json:"Amount"    gorm:"column:Amount; not null"` // Minor unit of the history Currency
+}
+
+// TaxRate is a tax applied to the charges of a stay
+type TaxRate struct {
+	Name      string
+	Rate      float64 // Percent
+	Inclusive bool    // Already included in the charges
+}
+
+// BasisPoints returns Rate in 1/100 of a percent
+func (t TaxRate) BasisPoints() int64 {
+	return int64(math.Round(t.Rate * 100))
 }
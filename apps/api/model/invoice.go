@@ -0,0 +1,57 @@
+package model
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	InvoiceKindInvoice    = "invoice"
+	InvoiceKindCreditNote = "credit_note"
+)
+
+// Invoice is a numbered receipt of a stay, or of every stay of a booking group. Its amounts and lines are copied
+// from the stays when it is issued, so later changes to them never alter an invoice; a wrong invoice is voided with
+// a credit note instead.
+type Invoice struct {
+	Id                uuid.UUID      `json:"Id"                gorm:"primary_key; column:Id; not null; type:char(36);"`
+	Number            string         `json:"Number"            gorm:"column:Number; not null; type:varchar(20); uniqueIndex"`
+	Kind              string         `json:"Kind"              gorm:"column:Kind; not null; type:varchar(20)"` // invoice or credit_note
+	HistoryId         *uuid.UUID     `json:"HistoryId"         gorm:"column:HistoryId; type:char(36); index"`  // Stay invoiced, empty for a group invoice
+	GroupId           *uuid.UUID     `json:"GroupId"           gorm:"column:GroupId; type:char(36); index"`    // BookingGroup invoiced as a whole
+	CustomerId        uuid.UUID      `json:"CustomerId"        gorm:"column:CustomerId; not null; type:char(36); index"`
+	CustomerName      string         `json:"CustomerName"      gorm:"column:CustomerName; not null"`
+	Currency          string         `json:"Currency"          gorm:"column:Currency; not null; type:char(3)"`
+	TaxAmount         int64          `json:"TaxAmount"         gorm:"column:TaxAmount; not null"` // Amounts are in the minor unit of Currency
+	TotalAmount       int64          `json:"TotalAmount"       gorm:"column:TotalAmount; not null"`
+	Paid              int64          `json:"Paid"              gorm:"column:Paid; not null"`
+	IssuedAt          time.Time      `json:"IssuedAt"          gorm:"column:IssuedAt; not null; index"`
+	VoidedAt          *time.Time     `json:"VoidedAt"          gorm:"column:VoidedAt"`
+	VoidReason        string         `json:"VoidReason"        gorm:"column:VoidReason"`
+	CreditNoteId      *uuid.UUID     `json:"CreditNoteId"      gorm:"column:CreditNoteId; type:char(36)"`      // Credit note voiding this invoice
+	OriginalInvoiceId *uuid.UUID     `json:"OriginalInvoiceId" gorm:"column:OriginalInvoiceId; type:char(36)"` // Invoice voided by this credit note
+	Note              string         `json:"Note"              gorm:"column:Note"`
+	Lines             []*InvoiceLine `json:"Lines"             gorm:"foreignKey:InvoiceId; references:Id"`
+}
+
+type InvoiceLine struct {
+	Id          uuid.UUID `json:"Id"          gorm:"primary_key; column:Id; not null; type:char(36);"`
+	InvoiceId   uuid.UUID `json:"InvoiceId"   gorm:"column:InvoiceId; not null; type:char(36); index"`
+	Position    int       `json:"Position"    gorm:"column:Position; not null"`
+	Description string    `json:"Description" gorm:"column:Description; not null"`
+	Amount      int64     `json:"Amount"      gorm:"column:Amount; not null"`   // Minor unit of the invoice Currency
+	Included    bool      `json:"Included"    gorm:"column:Included; not null"` // Tax already included in the lines above, not added to the total
+}
+
+// InvoiceSequence holds the last number used by a series in a year, so numbers are handed out without gaps
+type InvoiceSequence struct {
+	Series string `gorm:"primary_key; column:Series; not null; type:varchar(10)"`
+	Year   int    `gorm:"primary_key; column:Year; not null; autoIncrement:false"`
+	Last   int    `gorm:"column:Last; not null"`
+}
+
+// InvoiceNumber formats the number of an invoice, e.g. INV-2024-000042
+func InvoiceNumber(series string, year int, sequence int) string {
+	return fmt.Sprintf("%s-%d-%06d", series, year, sequence)
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Money is an amount in the minor unit of its ISO 4217 currency, e.g. cents for USD
+type Money struct {
+	Amount   int64  `json:"Amount"`
+	Currency string `json:"Currency"`
+}
+
+// minorUnits lists the currencies whose minor unit is not the usual 1/100
+var minorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "ISK": 0, "JPY": 0, "KRW": 0, "PYG": 0, "UGX": 0, "VND": 0, "XAF": 0, "XOF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// MinorUnits returns the number of decimals of the currency
+func MinorUnits(currency string) int {
+	if units, ok := minorUnits[currency]; ok {
+		return units
+	}
+	return 2
+}
+
+// NormalizeCurrency upper-cases a currency code and checks it has the three letters of ISO 4217
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if len(currency) != 3 {
+		return "", errors.New("error CRMS : Currency is invalid")
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return "", errors.New("error CRMS : Currency is invalid")
+		}
+	}
+	return currency, nil
+}
+
+// MoneyFromMajor converts a whole amount of the major unit, e.g. dollars, into Money
+func MoneyFromMajor(amount int, currency string) Money {
+	return Money{Amount: int64(amount) * pow10(MinorUnits(currency)), Currency: currency}
+}
+
+// Major returns the amount in the major unit, rounded half away from zero
+func (m Money) Major() int {
+	unit := pow10(MinorUnits(m.Currency))
+	if m.Amount < 0 {
+		return -int((-m.Amount + unit/2) / unit)
+	}
+	return int((m.Amount + unit/2) / unit)
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, errors.New("error CRMS : Currencies do not match")
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, errors.New("error CRMS : Currencies do not match")
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Tax returns the tax at basisPoints (1/100 of a percent) on the amount, rounded half up. An inclusive tax is
+// the part of the amount that is tax, an exclusive tax comes on top of it.
+func (m Money) Tax(basisPoints int64, inclusive bool) Money {
+	if inclusive {
+		net := (m.Amount*10000 + (10000+basisPoints)/2) / (10000 + basisPoints)
+		return Money{Amount: m.Amount - net, Currency: m.Currency}
+	}
+	return Money{Amount: (m.Amount*basisPoints + 5000) / 10000, Currency: m.Currency}
+}
+
+// Percent returns percent of the amount, rounded half up
+func (m Money) Percent(percent float64) Money {
+	basisPoints := int64(math.Round(percent * 100))
+	return Money{Amount: (m.Amount*basisPoints + 5000) / 10000, Currency: m.Currency}
+}
+
+// Decimal returns the amount in the major unit with the decimals of the currency, e.g. "12.30"
+func (m Money) Decimal() string {
+	units := MinorUnits(m.Currency)
+	if units == 0 {
+		return fmt.Sprintf("%d", m.Amount)
+	}
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	unit := pow10(units)
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, units, amount%unit)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func pow10(n int) int64 {
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	PaymentKindPayment = "payment"
+	PaymentKindRefund  = "refund"
+)
+
+// PaymentMethods lists the accepted ways of paying
+var PaymentMethods = []string{"cash", "card", "transfer", "mobile", "other"}
+
+// Payment is an entry of the ledger of a stay. A deposit taken for a reservation is linked to the stay when the
+// reservation checks in. Entries are never changed, a mistake is corrected with a refund.
+type Payment struct {
+	Id            uuid.UUID  `json:"Id"            gorm:"primary_key; column:Id; not null; type:char(36);"`
+	HistoryId     *uuid.UUID `json:"HistoryId"     gorm:"column:HistoryId; type:char(36); index"`
+	ReservationId *uuid.UUID `json:"ReservationId" gorm:"column:ReservationId; type:char(36); index"`
+	Kind          string     `json:"Kind"          gorm:"column:Kind; not null; type:varchar(20)"` // payment or refund
+	Method        string     `json:"Method"        gorm:"column:Method; not null; type:varchar(20)"`
+	Amount        int64      `json:"Amount"        gorm:"column:Amount; not null"` // Minor unit of Currency, always positive
+	Currency      string     `json:"Currency"      gorm:"column:Currency; not null; type:char(3)"`
+	Reference     string     `json:"Reference"     gorm:"column:Reference; type:varchar(100)"`
+	ReceivedBy    string     `json:"ReceivedBy"    gorm:"column:ReceivedBy; not null; type:varchar(100)"`
+	PaidAt        time.Time  `json:"PaidAt"        gorm:"column:PaidAt; not null; index"`
+	Note          string     `json:"Note"          gorm:"column:Note"`
+	CreatedAt     time.Time  `json:"CreatedAt"     gorm:"column:CreatedAt; not null"`
+}
+
+// Signed returns the amount the entry adds to what the guest has paid
+func (p *Payment) Signed() int64 {
+	if p.Kind == PaymentKindRefund {
+		return -p.Amount
+	}
+	return p.Amount
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type RatePlan struct {
+	Id              uuid.UUID         `json:"Id"              gorm:"primary_key; column:Id; not null; type:char(36);"`
+	Name            string            `json:"Name"            gorm:"column:Name; not null; type:varchar(100)"`
+	RoomType        string            `json:"RoomType"        gorm:"column:RoomType; not null; type:varchar(50); index"` // Room.Type the plan prices
+	BaseOccupancy   int               `json:"BaseOccupancy"   gorm:"column:BaseOccupancy; not null"`                     // People included in the nightly rate
+	WeekdayRate     int               `json:"WeekdayRate"     gorm:"column:WeekdayRate; not null"`
+	WeekendRate     int               `json:"WeekendRate"     gorm:"column:WeekendRate; not null"`     // Rate of Friday and Saturday nights
+	ExtraPersonRate int               `json:"ExtraPersonRate" gorm:"column:ExtraPersonRate; not null"` // Surcharge per person beyond BaseOccupancy per night
+	Active          bool              `json:"Active"          gorm:"column:Active; not null"`
+	Note            string            `json:"Note"            gorm:"column:Note"`
+	Seasons         []*RatePlanSeason `json:"Seasons"         gorm:"foreignKey:RatePlanId; references:Id"`
+}
+
+// RatePlanSeason replaces the rates of its RatePlan on the nights from StartDate to EndDate, both included.
+// A rate left at 0 keeps the rate of the plan.
+type RatePlanSeason struct {
+	Id              uuid.UUID `json:"Id"              gorm:"primary_key; column:Id; not null; type:char(36);"`
+	RatePlanId      uuid.UUID `json:"RatePlanId"      gorm:"column:RatePlanId; not null; type:char(36); index"`
+	Name            string    `json:"Name"            gorm:"column:Name; not null; type:varchar(100)"`
+	StartDate       time.Time `json:"StartDate"       gorm:"column:StartDate; not null"`
+	EndDate         time.Time `json:"EndDate"         gorm:"column:EndDate; not null"`
+	WeekdayRate     int       `json:"WeekdayRate"     gorm:"column:WeekdayRate; not null"`
+	WeekendRate     int       `json:"WeekendRate"     gorm:"column:WeekendRate; not null"`
+	ExtraPersonRate int       `json:"ExtraPersonRate" gorm:"column:ExtraPersonRate; not null"`
+}
+
+// Covers reports whether the night starting on date falls in the season
+func (s *RatePlanSeason) Covers(date time.Time) bool {
+	return !date.Before(s.StartDate) && !date.After(s.EndDate)
+}
+
+// IsWeekendNight reports whether the night starting on date is priced at the weekend rate
+func IsWeekendNight(date time.Time) bool {
+	return date.Weekday() == time.Friday || date.Weekday() == time.Saturday
+}
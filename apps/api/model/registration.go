@@ -0,0 +1,33 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	RegistrationFormatCSV   = "csv"
+	RegistrationFormatFixed = "fixed"
+)
+
+// RegistrationSubmission records that a foreign guest of a stay was reported to the authorities
+type RegistrationSubmission struct {
+	Id          uuid.UUID `json:"Id"          gorm:"primary_key; column:Id; not null; type:char(36);"`
+	HistoryId   uuid.UUID `json:"HistoryId"   gorm:"column:HistoryId; not null; type:char(36); uniqueIndex:idx_registration_submission"`
+	CustomerId  uuid.UUID `json:"CustomerId"  gorm:"column:CustomerId; not null; type:char(36); uniqueIndex:idx_registration_submission; index"`
+	Date        time.Time `json:"Date"        gorm:"column:Date; not null"` // Night the report was made for
+	SubmittedAt time.Time `json:"SubmittedAt" gorm:"column:SubmittedAt; not null"`
+}
+
+// RegistrationField is a column of the foreign-guest registration file
+type RegistrationField struct {
+	Field string
+	Label string // Header of CSV files
+	Width int    // Characters of fixed-width files
+}
+
+// RegistrationLayout is the file the authorities expect foreign guests reported in
+type RegistrationLayout struct {
+	Format string // csv or fixed
+	Fields []RegistrationField
+}
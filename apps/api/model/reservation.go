@@ -0,0 +1,38 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	ReservationTentative  = "tentative"
+	ReservationConfirmed  = "confirmed"
+	ReservationCheckedIn  = "checked-in"
+	ReservationCheckedOut = "checked-out"
+	ReservationCancelled  = "cancelled"
+	ReservationNoShow     = "no-show"
+)
+
+type Reservation struct {
+	Id                   uuid.UUID  `json:"Id"                   gorm:"primary_key; column:Id; not null; type:char(36);"`
+	CustomerId           uuid.UUID  `json:"CustomerId"           gorm:"column:CustomerId; not null; type:char(36); index"`
+	RoomId               uuid.UUID  `json:"RoomId"               gorm:"column:RoomId; not null; type:char(36); index"`
+	Room                 Room       `                            gorm:"foreignKey:RoomId; references:Id"`
+	CheckIn              time.Time  `json:"CheckIn"              gorm:"column:CheckIn; not null; index"`
+	CheckOut             time.Time  `json:"CheckOut"             gorm:"column:CheckOut; not null; index"`
+	NumberOfPeople       int        `json:"NumberOfPeople"       gorm:"column:NumberOfPeople; not null"`
+	Price                int        `json:"Price"                gorm:"column:Price; not null"`
+	Status               string     `json:"Status"               gorm:"column:Status; not null; type:varchar(20); index"`
+	HistoryId            *uuid.UUID `json:"HistoryId"            gorm:"column:HistoryId; type:char(36)"` // Stay, or fee charged when cancelled or no-show
+	CancellationPolicyId *uuid.UUID `json:"CancellationPolicyId" gorm:"column:CancellationPolicyId; type:char(36); index"`
+	GroupId              *uuid.UUID `json:"GroupId"              gorm:"column:GroupId; type:char(36); index"` // BookingGroup the reservation belongs to
+	Note                 string     `json:"Note"                 gorm:"column:Note"`
+	CreatedAt            time.Time  `json:"CreatedAt"            gorm:"column:CreatedAt; not null"`
+	UpdatedAt            time.Time  `json:"UpdatedAt"            gorm:"column:UpdatedAt; not null"`
+}
+
+// Nights returns the number of nights between CheckIn and CheckOut
+func (r *Reservation) Nights() int {
+	return NightsBetween(r.CheckIn, r.CheckOut)
+}
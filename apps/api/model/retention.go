@@ -0,0 +1,15 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type RetentionRule struct {
+	Id            uuid.UUID `json:"Id"            gorm:"primary_key; column:Id; not null; type:char(36);"`
+	Entity        string    `json:"Entity"        gorm:"column:Entity; not null; type:varchar(20)"`
+	CitizenshipId int       `json:"CitizenshipId" gorm:"column:CitizenshipId; not null; default:0"`
+	Years         int       `json:"Years"         gorm:"column:Years; not null"`
+	Action        string    `json:"Action"        gorm:"column:Action; not null; type:varchar(20)"`
+	Active        bool      `json:"Active"        gorm:"column:Active; not null"`
+	Note          string    `json:"Note"          gorm:"column:Note"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"strings"
+)
+
+type Room struct {
+	Id       uuid.UUID `json:"Id"       gorm:"primary_key; column:Id; not null; type:char(36);"`
+	Number   string    `json:"Number"   gorm:"column:Number; not null; type:varchar(20); uniqueIndex;"`
+	Type     string    `json:"Type"     gorm:"column:Type; not null; type:varchar(50); index"`
+	Capacity int       `json:"Capacity" gorm:"column:Capacity; not null"`
+	BaseRate int       `json:"BaseRate" gorm:"column:BaseRate; not null"`
+	Active   bool      `json:"Active"   gorm:"column:Active; not null"`
+}
+
+// NormalizeRoomNumber strips the decorations people type around a room number, so "Room 201", "#201" and "201 " are all "201"
+func NormalizeRoomNumber(number string) string {
+	number = strings.TrimSpace(number)
+	lower := strings.ToLower(number)
+	for _, prefix := range []string{"room", "rm.", "rm", "no.", "#", "房"} {
+		if strings.HasPrefix(lower, prefix) {
+			number = strings.TrimSpace(number[len(prefix):])
+			lower = strings.ToLower(number)
+		}
+	}
+	return strings.ToUpper(strings.TrimSuffix(number, "號房"))
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// RoomOutage takes a room out of service, e.g. for repairs, so it is neither offered nor counted as inventory
+type RoomOutage struct {
+	Id        uuid.UUID `json:"Id"        gorm:"primary_key; column:Id; not null; type:char(36);"`
+	RoomId    uuid.UUID `json:"RoomId"    gorm:"column:RoomId; not null; type:char(36); index"`
+	Room      Room      `                 gorm:"foreignKey:RoomId; references:Id"`
+	StartDate time.Time `json:"StartDate" gorm:"column:StartDate; not null; index"` // First night out of service
+	EndDate   time.Time `json:"EndDate"   gorm:"column:EndDate; not null; index"`   // Last night out of service
+	Reason    string    `json:"Reason"    gorm:"column:Reason; type:varchar(200)"`
+	CreatedAt time.Time `json:"CreatedAt" gorm:"column:CreatedAt; not null"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// StayGuest registers a person staying in the room of a History. The primary guest is the customer of the History,
+// every other guest is a companion.
+type StayGuest struct {
+	Id         uuid.UUID `json:"Id"         gorm:"primary_key; column:Id; not null; type:char(36);"`
+	HistoryId  uuid.UUID `json:"HistoryId"  gorm:"column:HistoryId; not null; type:char(36); uniqueIndex:idx_stay_guest"`
+	CustomerId uuid.UUID `json:"CustomerId" gorm:"column:CustomerId; not null; type:char(36); uniqueIndex:idx_stay_guest; index"`
+	IsPrimary  bool      `json:"IsPrimary"  gorm:"column:IsPrimary; not null; default:false"`
+	Customer   Customer  `                  gorm:"foreignKey:CustomerId; references:Id"`
+}
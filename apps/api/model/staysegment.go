@@ -0,0 +1,17 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// StaySegment is the part of a stay spent in one room. Every stay has at least one; a room move ends the current
+// segment on the move date and opens another in the new room, so the nights keep the room they were spent in.
+type StaySegment struct {
+	Id        uuid.UUID `json:"Id"        gorm:"primary_key; column:Id; not null; type:char(36);"`
+	HistoryId uuid.UUID `json:"HistoryId" gorm:"column:HistoryId; not null; type:char(36); index"`
+	RoomId    uuid.UUID `json:"RoomId"    gorm:"column:RoomId; not null; type:char(36); index"`
+	Room      Room      `                 gorm:"foreignKey:RoomId; references:Id"`
+	CheckIn   time.Time `json:"CheckIn"   gorm:"column:CheckIn; not null; index"`
+	CheckOut  time.Time `json:"CheckOut"  gorm:"column:CheckOut; not null; index"`
+}
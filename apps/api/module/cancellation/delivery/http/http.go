@@ -0,0 +1,237 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type CancellationPolicyHandler struct {
+	ser domain.CancellationPolicyService
+}
+
+func NewCancellationPolicyHandler(e *gin.Engine, ser domain.CancellationPolicyService) {
+	handler := &CancellationPolicyHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/cancellationPolicyList", handler.ListCancellationPolicies)
+		api.POST("/cancellationPolicyId", handler.GetCancellationPolicyById)
+		api.POST("/cancellationPolicyCre", handler.CreateCancellationPolicy)
+		api.POST("/cancellationPolicyMod", handler.ModifyCancellationPolicy)
+		api.POST("/cancellationPolicyDel", handler.DeleteCancellationPolicy)
+	}
+}
+
+// ListCancellationPolicies @Summary ListCancellationPolicies
+// @Description Get all CancellationPolicies, the default one first
+// @Tags CancellationPolicy
+// @Produce application/json
+// @Success 200 {object} []model.CancellationPolicy
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /cancellationPolicyList [post]
+func (u *CancellationPolicyHandler) ListCancellationPolicies(c *gin.Context) {
+	policies, err := u.ser.ListCancellationPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":  "List all cancellation policies",
+		"policies": policies,
+	})
+}
+
+// GetCancellationPolicyById @Summary GetCancellationPolicyById
+// @Description Get CancellationPolicy by PolicyId
+// @Tags CancellationPolicy
+// @Produce application/json
+// @Param PolicyId body dto.CancellationPolicyIdRequest true "CancellationPolicy id"
+// @Success 200 {object} model.CancellationPolicy
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /cancellationPolicyId [post]
+func (u *CancellationPolicyHandler) GetCancellationPolicyById(c *gin.Context) {
+	request := dto.CancellationPolicyIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	policy, err := u.ser.GetCancellationPolicyById(request.PolicyId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this cancellation policy" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// CreateCancellationPolicy @Summary CreateCancellationPolicy
+// @Description Create a new CancellationPolicy
+// @Tags CancellationPolicy
+// @Accept json
+// @Produce application/json
+// @Param CancellationPolicy body dto.CancellationPolicyRequest true "CancellationPolicy Information" example: {"Name": "Flexible", "FreeDays": 3, "LateFee": 50, "NoShowFee": 100, "IsDefault": true, "Active": true}
+// @Success 200 {object} model.CancellationPolicy
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /cancellationPolicyCre [post]
+func (u *CancellationPolicyHandler) CreateCancellationPolicy(c *gin.Context) {
+	request := dto.CancellationPolicyRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	policy, err := u.ser.CreateCancellationPolicy(transformToCancellationPolicy(request))
+	if err != nil {
+		if err.Error() == "error CRMS : Cancellation policy Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Fee must be between 0 and 100 percent" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : The default cancellation policy must be active" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Cancellation policy name is already used" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// ModifyCancellationPolicy @Summary ModifyCancellationPolicy
+// @Description Modify CancellationPolicy data. Reservations already booked under it get the new fees
+// @Tags CancellationPolicy
+// @Accept json
+// @Produce application/json
+// @Param CancellationPolicy body dto.CancellationPolicyRequest true "CancellationPolicy Information"
+// @Success 200 {object} model.CancellationPolicy
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /cancellationPolicyMod [post]
+func (u *CancellationPolicyHandler) ModifyCancellationPolicy(c *gin.Context) {
+	request := dto.CancellationPolicyRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	policy, err := u.ser.UpdateCancellationPolicy(transformToCancellationPolicy(request))
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this cancellation policy" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Cancellation policy Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Fee must be between 0 and 100 percent" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : The default cancellation policy must be active" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Cancellation policy name is already used" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeleteCancellationPolicy @Summary DeleteCancellationPolicy
+// @Description Delete CancellationPolicy by PolicyId
+// @Tags CancellationPolicy
+// @Produce application/json
+// @Param PolicyId body dto.CancellationPolicyIdRequest true "CancellationPolicy id"
+// @Success 200 {object} string "Message": "Delete success"
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /cancellationPolicyDel [post]
+func (u *CancellationPolicyHandler) DeleteCancellationPolicy(c *gin.Context) {
+	request := dto.CancellationPolicyIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	err := u.ser.DeleteCancellationPolicy(request.PolicyId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this cancellation policy" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This cancellation policy has reservations, deactivate it instead" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "Delete success",
+	})
+}
+
+func transformToCancellationPolicy(requestData dto.CancellationPolicyRequest) *model.CancellationPolicy {
+	return &model.CancellationPolicy{
+		Id:        requestData.PolicyId,
+		Name:      requestData.Name,
+		FreeDays:  requestData.FreeDays,
+		LateFee:   requestData.LateFee,
+		NoShowFee: requestData.NoShowFee,
+		IsDefault: requestData.IsDefault,
+		Active:    requestData.Active,
+		Note:      requestData.Note,
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"gorm.io/gorm"
+)
+
+type CancellationPolicyRepository struct {
+	orm *gorm.DB
+}
+
+func NewCancellationPolicyRepository(orm *gorm.DB) domain.CancellationPolicyRepository {
+	return &CancellationPolicyRepository{
+		orm: orm,
+	}
+}
+
+func (u *CancellationPolicyRepository) ListCancellationPolicies() ([]*model.CancellationPolicy, error) {
+	var policies []*model.CancellationPolicy
+	err := u.orm.Order("IsDefault DESC").Order("Name").Find(&policies).Error
+	return policies, err
+}
+
+func (u *CancellationPolicyRepository) GetCancellationPolicyById(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) {
+	err := u.orm.Where("Id = ?", policy.Id).Find(&policy).Error
+	return policy, err
+}
+
+func (u *CancellationPolicyRepository) GetCancellationPolicyByName(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) {
+	err := u.orm.Where("Name = ?", policy.Name).Find(&policy).Error
+	return policy, err
+}
+
+func (u *CancellationPolicyRepository) CreateCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefault(tx, policy); err != nil {
+			return err
+		}
+		return tx.Create(&policy).Error
+	})
+	return policy, err
+}
+
+func (u *CancellationPolicyRepository) UpdateCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefault(tx, policy); err != nil {
+			return err
+		}
+		return tx.Model(policy).Where("Id = ?", policy.Id).Select("*").Updates(&policy).Error
+	})
+	return policy, err
+}
+
+func (u *CancellationPolicyRepository) DeleteCancellationPolicy(policy *model.CancellationPolicy) error {
+	return u.orm.Where("Id = ?", policy.Id).Delete(&model.CancellationPolicy{}).Error
+}
+
+func (u *CancellationPolicyRepository) CountReservationsByCancellationPolicy(policy *model.CancellationPolicy) (int64, error) {
+	var count int64
+	err := u.orm.Model(&model.Reservation{}).Where("CancellationPolicyId = ?", policy.Id).Count(&count).Error
+	return count, err
+}
+
+// clearDefault unsets the previous default policy when policy becomes the default, so there is at most one
+func clearDefault(tx *gorm.DB, policy *model.CancellationPolicy) error {
+	if !policy.IsDefault {
+		return nil
+	}
+	return tx.Model(&model.CancellationPolicy{}).Where("IsDefault = ? AND Id <> ?", true, policy.Id).Update("IsDefault", false).Error
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+)
+
+type CancellationPolicyService struct {
+	repo domain.CancellationPolicyRepository
+}
+
+func NewCancellationPolicyService(repo domain.CancellationPolicyRepository) domain.CancellationPolicyService {
+	return &CancellationPolicyService{
+		repo: repo,
+	}
+}
+
+func (u *CancellationPolicyService) ListCancellationPolicies() ([]*model.CancellationPolicy, error) {
+	var err error
+	var policies []*model.CancellationPolicy
+	if policies, err = u.repo.ListCancellationPolicies(); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfCancellationPolicy(policies), err
+}
+
+func (u *CancellationPolicyService) GetCancellationPolicyById(policyId uuid.UUID) (*model.CancellationPolicy, error) {
+	var err error
+	newPolicy := &model.CancellationPolicy{
+		Id: policyId,
+	}
+	if newPolicy, err = u.repo.GetCancellationPolicyById(newPolicy); err != nil {
+		return nil, err
+	} else if newPolicy.Name == "" {
+		return nil, errors.New("error CRMS : There is no this cancellation policy")
+	}
+	return newPolicy, err
+}
+
+func (u *CancellationPolicyService) CreateCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) {
+	var err error
+	if err = validateCancellationPolicyInfo(policy); err != nil {
+		return nil, err
+	}
+	if err = u.confirmNameUnused(policy); err != nil {
+		return nil, err
+	}
+	policy.Id = uuid.New()
+	return u.repo.CreateCancellationPolicy(policy)
+}
+
+func (u *CancellationPolicyService) UpdateCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) {
+	var err error
+	if _, err = u.GetCancellationPolicyById(policy.Id); err != nil {
+		return nil, err
+	}
+	if err = validateCancellationPolicyInfo(policy); err != nil {
+		return nil, err
+	}
+	if err = u.confirmNameUnused(policy); err != nil {
+		return nil, err
+	}
+	return u.repo.UpdateCancellationPolicy(policy)
+}
+
+func (u *CancellationPolicyService) DeleteCancellationPolicy(policyId uuid.UUID) error {
+	var err error
+	var policy *model.CancellationPolicy
+	var count int64
+	if policy, err = u.GetCancellationPolicyById(policyId); err != nil {
+		return err
+	}
+	if count, err = u.repo.CountReservationsByCancellationPolicy(policy); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This cancellation policy has reservations, deactivate it instead")
+	}
+	return u.repo.DeleteCancellationPolicy(policy)
+}
+
+func (u *CancellationPolicyService) confirmNameUnused(policy *model.CancellationPolicy) error {
+	var err error
+	existing := &model.CancellationPolicy{
+		Name: policy.Name,
+	}
+	if existing, err = u.repo.GetCancellationPolicyByName(existing); err != nil {
+		return err
+	} else if existing.Id != uuid.Nil && existing.Id != policy.Id {
+		return errors.New("error CRMS : Cancellation policy name is already used")
+	}
+	return nil
+}
+
+func convertToSliceOfCancellationPolicy(policies []*model.CancellationPolicy) []*model.CancellationPolicy {
+	var policiesSlice []*model.CancellationPolicy
+	for _, policy := range policies {
+		policiesSlice = append(policiesSlice, policy)
+	}
+	return policiesSlice
+}
+
+func validateCancellationPolicyInfo(policy *model.CancellationPolicy) error {
+	if policy.Name == "" || policy.FreeDays < 0 {
+		return errors.New("error CRMS : Cancellation policy Info is incomplete")
+	}
+	if policy.LateFee < 0 || policy.LateFee > 100 || policy.NoShowFee < 0 || policy.NoShowFee > 100 {
+		return errors.New("error CRMS : Fee must be between 0 and 100 percent")
+	}
+	if policy.IsDefault && !policy.Active {
+		return errors.New("error CRMS : The default cancellation policy must be active")
+	}
+	return nil
+}
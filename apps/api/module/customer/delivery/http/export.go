@@ -0,0 +1,97 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/S1nceU/CRMS/apps/api/sheet"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"time"
+)
+
+var customerColumns = []sheet.Column[*model.Customer]{
+	{Key: "Id", Labels: map[string]string{"en": "Id", "zh-TW": "編號"}, Value: func(c *model.Customer) interface{} { return c.Id.String() }},
+	{Key: "Name", Labels: map[string]string{"en": "Name", "zh-TW": "姓名"}, Value: func(c *model.Customer) interface{} { return c.Name }},
+	{Key: "Gender", Labels: map[string]string{"en": "Gender", "zh-TW": "性別"}, Value: func(c *model.Customer) interface{} { return c.Gender }},
+	{Key: "Birthday", Labels: map[string]string{"en": "Birthday", "zh-TW": "生日"}, Value: func(c *model.Customer) interface{} { return c.Birthday }},
+	{Key: "NationalId", Labels: map[string]string{"en": "National ID", "zh-TW": "身分證字號"}, Value: func(c *model.Customer) interface{} { return c.NationalId }},
+	{Key: "Address", Labels: map[string]string{"en": "Address", "zh-TW": "地址"}, Value: func(c *model.Customer) interface{} { return c.Address }},
+	{Key: "PhoneNumber", Labels: map[string]string{"en": "Phone Number", "zh-TW": "電話"}, Value: func(c *model.Customer) interface{} { return c.PhoneNumber }},
+	{Key: "CarNumber", Labels: map[string]string{"en": "Car Number", "zh-TW": "車號"}, Value: func(c *model.Customer) interface{} { return c.CarNumber }},
+	{Key: "Citizenship", Labels: map[string]string{"en": "Citizenship", "zh-TW": "國籍"}, Value: func(c *model.Customer) interface{} { return c.Citizenship.Nation }},
+	{Key: "Alpha3", Labels: map[string]string{"en": "Alpha3", "zh-TW": "國家代碼"}, Value: func(c *model.Customer) interface{} { return c.Citizenship.Alpha3 }},
+	{Key: "Note", Labels: map[string]string{"en": "Note", "zh-TW": "備註"}, Value: func(c *model.Customer) interface{} { return c.Note }},
+}
+
+// ExportCustomers @Summary ExportCustomers
+// @Description Download the Customers matching the filter as CSV, XLSX or JSON Lines
+// @Tags Customer
+// @Accept json
+// @Produce application/octet-stream
+// @Param Export body dto.CustomerExportRequest true "Format, columns, header language and filter" example: {"Format": "csv", "Columns": ["Name", "PhoneNumber"], "Language": "zh-TW", "Citizenship": 1}
+// @Success 200 {file} file
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /customerListExport [post]
+func (u *CustomerHandler) ExportCustomers(c *gin.Context) {
+	request := dto.CustomerExportRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	contentType, extension, err := sheet.ContentType(request.Format)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	columns, err := sheet.SelectColumns(customerColumns, request.Columns)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	keys, labels := sheet.Header(columns, request.Language)
+
+	var writer sheet.Writer
+	open := func() error {
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", "attachment; filename=customers-"+time.Now().Format("20060102")+extension)
+		c.Status(http.StatusOK)
+		if writer, err = sheet.NewWriter(request.Format, c.Writer); err != nil {
+			return err
+		}
+		return writer.WriteHeader(keys, labels)
+	}
+	err = u.customerSer.StreamCustomers(&request.CustomerFilter, func(customer *model.Customer) error {
+		if writer == nil {
+			if err := open(); err != nil {
+				return err
+			}
+		}
+		return writer.WriteRow(sheet.Values(columns, customer))
+	})
+	if err != nil && writer == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		log.Println("Export customers failed:", err)
+		return
+	}
+	if writer == nil {
+		if err = open(); err != nil {
+			log.Println("Export customers failed:", err)
+			return
+		}
+	}
+	if err = writer.Close(); err != nil {
+		log.Println("Export customers failed:", err)
+	}
+}
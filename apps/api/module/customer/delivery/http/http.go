@@ -28,6 +28,12 @@ func NewCustomerHandler(e *gin.Engine, customerSer domain.CustomerService) {
 		api.POST("/customerCitizenship", handler.ListCustomersByCitizenship)
 		api.POST("/customerPhone", handler.GetCustomerByCustomerPhone)
 		api.POST("/customerID", handler.GetCustomerByCustomerID)
+		api.POST("/customerExport", handler.ExportCustomerData)
+		api.POST("/customerErase", handler.EraseCustomer)
+		api.POST("/customerImport", handler.ImportCustomers)
+		api.POST("/customerListExport", handler.ExportCustomers)
+		api.POST("/customerVCard", handler.GetCustomerVCard)
+		api.POST("/customerListVCard", handler.ListCustomersVCard)
 	}
 }
 
@@ -205,6 +211,21 @@ func (u *CustomerHandler) DeleteCustomer(c *gin.Context) {
 				"Message": err.Error(),
 			})
 			return
+		} else if err.Error() == "error CRMS : This customer has payments and cannot be deleted" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This customer has invoices and cannot be deleted" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This customer has open reservations and cannot be deleted" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"Message": err.Error(),
@@ -370,6 +391,81 @@ func (u *CustomerHandler) GetCustomerByCustomerID(c *gin.Context) {
 	c.JSON(http.StatusOK, customerData)
 }
 
+// ExportCustomerData @Summary ExportCustomerData
+// @Description Download every data CRMS keeps about a Customer as a JSON package
+// @Tags Customer
+// @Produce application/json
+// @Param CustomerId body dto.CustomerIdRequest true "Customer id"
+// @Success 200 {object} dto.CustomerDataPackage
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /customerExport [post]
+func (u *CustomerHandler) ExportCustomerData(c *gin.Context) {
+	request := dto.CustomerIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	dataPackage, err := u.customerSer.ExportCustomerData(request.CustomerId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this customer" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.Header("Content-Disposition", "attachment; filename=customer-"+request.CustomerId.String()+".json")
+	c.JSON(http.StatusOK, dataPackage)
+}
+
+// EraseCustomer @Summary EraseCustomer
+// @Description Anonymize the identifying data of a Customer while keeping the stays for accounting
+// @Tags Customer
+// @Accept json
+// @Produce application/json
+// @Param Customer body dto.CustomerEraseRequest true "Customer id and reason"
+// @Success 200 {object} string "Message": "Erase success"
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /customerErase [post]
+func (u *CustomerHandler) EraseCustomer(c *gin.Context) {
+	request := dto.CustomerEraseRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	err := u.customerSer.EraseCustomer(request.CustomerId, request.Reason)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this customer" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This customer is already anonymized" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "Erase success",
+	})
+}
+
 func transformToCustomer(requestData dto.CustomerRequest) (*model.Customer, error) {
 	birthday, err := time.ParseInLocation("2006-01-02", requestData.Birthday, time.Local)
 	if err != nil {
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/S1nceU/CRMS/apps/api/sheet"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// importColumns maps normalized header names to dto.CustomerRequest fields
+var importColumns = map[string]string{
+	"name":          "Name",
+	"gender":        "Gender",
+	"birthday":      "Birthday",
+	"nationalid":    "NationalId",
+	"passport":      "NationalId",
+	"address":       "Address",
+	"phonenumber":   "PhoneNumber",
+	"phone":         "PhoneNumber",
+	"carnumber":     "CarNumber",
+	"citizenship":   "Citizenship",
+	"citizenshipid": "Citizenship",
+	"nation":        "Citizenship",
+	"alpha3":        "Citizenship",
+	"note":          "Note",
+}
+
+// ImportCustomers @Summary ImportCustomers
+// @Description Create or update Customers by NationalId from a CSV or XLSX file
+// @Tags Customer
+// @Accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "CSV or XLSX file, the first row is the header"
+// @Param dryRun formData bool false "Validate only, do not write"
+// @Param mapping formData string false "JSON object mapping file headers to Customer fields" example: {"Passport No": "NationalId"}
+// @Success 200 {object} dto.CustomerImportResult
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /customerImport [post]
+func (u *CustomerHandler) ImportCustomers(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.PostForm("dryRun"))
+	mapping := map[string]string{}
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err = json.Unmarshal([]byte(raw), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+	records, err := sheet.ReadRows(fileHeader.Filename, file)
+	if err != nil {
+		if err.Error() == "error CRMS : Unsupported file format" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	if len(records) < 2 {
+		c.JSON(http.StatusOK, gin.H{
+			"Message": "error CRMS : There is no customer in the file",
+		})
+		return
+	}
+
+	result, err := u.customerSer.ImportCustomers(transformToImportRows(records, mapping), dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func transformToImportRows(records [][]string, mapping map[string]string) []*dto.CustomerImportRow {
+	fields := make([]string, len(records[0]))
+	for i, header := range records[0] {
+		if field, ok := mapping[header]; ok {
+			fields[i] = field
+		} else {
+			fields[i] = importColumns[normalizeHeader(header)]
+		}
+	}
+
+	var rows []*dto.CustomerImportRow
+	for i, record := range records[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+		row := &dto.CustomerImportRow{
+			Row: i + 2,
+		}
+		for j, value := range record {
+			if j >= len(fields) {
+				break
+			}
+			switch fields[j] {
+			case "Name":
+				row.Customer.Name = value
+			case "Gender":
+				row.Customer.Gender = value
+			case "Birthday":
+				row.Customer.Birthday = value
+			case "NationalId":
+				row.Customer.NationalId = value
+			case "Address":
+				row.Customer.Address = value
+			case "PhoneNumber":
+				row.Customer.PhoneNumber = value
+			case "CarNumber":
+				row.Customer.CarNumber = value
+			case "Citizenship":
+				row.Citizenship = value
+			case "Note":
+				row.Customer.Note = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func normalizeHeader(header string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.TrimSpace(header)))
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
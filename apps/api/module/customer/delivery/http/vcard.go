@@ -0,0 +1,108 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/S1nceU/CRMS/apps/api/vcard"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+)
+
+// GetCustomerVCard @Summary GetCustomerVCard
+// @Description Download the contact details of a Customer as a vCard
+// @Tags Customer
+// @Accept json
+// @Produce text/vcard
+// @Param Customer body dto.CustomerVCardRequest true "Customer id and vCard version" example: {"CustomerId": "00000000-0000-0000-0000-000000000000", "Version": "4.0"}
+// @Success 200 {file} file
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /customerVCard [post]
+func (u *CustomerHandler) GetCustomerVCard(c *gin.Context) {
+	request := dto.CustomerVCardRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	version, err := vcard.ValidateVersion(request.Version)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	customerData, err := u.customerSer.GetCustomerByCustomerId(request.CustomerId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this customer" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.Header("Content-Type", "text/vcard; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=customer-"+customerData.Id.String()+".vcf")
+	c.Status(http.StatusOK)
+	if err = vcard.Write(c.Writer, version, customerData); err != nil {
+		log.Println("Export vCard failed:", err)
+	}
+}
+
+// ListCustomersVCard @Summary ListCustomersVCard
+// @Description Download the contact details of the Customers matching the filter as one vCard file
+// @Tags Customer
+// @Accept json
+// @Produce text/vcard
+// @Param Filter body dto.CustomerListVCardRequest true "Filter and vCard version" example: {"Name": "Wang", "Version": "3.0"}
+// @Success 200 {file} file
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /customerListVCard [post]
+func (u *CustomerHandler) ListCustomersVCard(c *gin.Context) {
+	request := dto.CustomerListVCardRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	version, err := vcard.ValidateVersion(request.Version)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+
+	started := false
+	err = u.customerSer.StreamCustomers(&request.CustomerFilter, func(customer *model.Customer) error {
+		if !started {
+			c.Header("Content-Type", "text/vcard; charset=utf-8")
+			c.Header("Content-Disposition", "attachment; filename=customers.vcf")
+			c.Status(http.StatusOK)
+			started = true
+		}
+		return vcard.Write(c.Writer, version, customer)
+	})
+	if err != nil {
+		if !started {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+		log.Println("Export vCard failed:", err)
+		return
+	}
+	if !started {
+		c.JSON(http.StatusOK, gin.H{
+			"Message": "error CRMS : There is no this customer",
+		})
+	}
+}
@@ -3,9 +3,17 @@ package repository
 import (
 	"github.com/S1nceU/CRMS/apps/api/domain"
 	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// streamBatchSize is the number of rows fetched per query while streaming
+const streamBatchSize = 500
+
+// importBatchSize is the number of imported customers written per statement, all of them in one transaction
+const importBatchSize = 500
+
 type CustomerRepository struct {
 	orm *gorm.DB
 }
@@ -41,12 +49,12 @@ func (u *CustomerRepository) ListCustomersByPhone(customer *model.Customer) ([]*
 }
 
 func (u *CustomerRepository) GetCustomerByNationalId(customer *model.Customer) (*model.Customer, error) {
-	err := u.orm.Preload("Citizenship").Preload("Histories").Where("NationalId = ?", customer.NationalId).Find(&customer).Error
+	err := u.orm.Preload("Citizenship").Preload("Histories.Room").Preload("Histories.TaxLines").Where("NationalId = ?", customer.NationalId).Find(&customer).Error
 	return customer, err
 }
 
 func (u *CustomerRepository) GetCustomerByCustomerId(customer *model.Customer) (*model.Customer, error) {
-	err := u.orm.Preload("Citizenship").Preload("Histories").Where("Id = ?", customer.Id).Find(&customer).Error
+	err := u.orm.Preload("Citizenship").Preload("Histories.Room").Preload("Histories.TaxLines").Where("Id = ?", customer.Id).Find(&customer).Error
 	return customer, err
 }
 
@@ -69,6 +77,180 @@ func (u *CustomerRepository) UpdateCustomer(customer *model.Customer) (*model.Cu
 }
 
 func (u *CustomerRepository) DeleteCustomer(customer *model.Customer) error {
-	u.orm.Where("CustomerId = ?", customer.Id).Delete(&model.History{})
-	return u.orm.Where("Id = ?", customer.Id).Delete(&customer).Error
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		stays := tx.Model(&model.History{}).Select("Id").Where("CustomerId = ?", customer.Id)
+		if err := tx.Where("HistoryId IN (?)", stays).Delete(&model.HistoryTaxLine{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?) OR CustomerId = ?", stays, customer.Id).Delete(&model.StayGuest{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?)", stays).Delete(&model.StaySegment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?) OR CustomerId = ?", stays, customer.Id).Delete(&model.RegistrationSubmission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("CustomerId = ?", customer.Id).Delete(&model.Reservation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("CustomerId = ?", customer.Id).Delete(&model.History{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("Id = ?", customer.Id).Delete(&customer).Error
+	})
+}
+
+func (u *CustomerRepository) CountPaymentsByCustomer(customer *model.Customer) (int64, error) {
+	var count int64
+	err := u.orm.Model(&model.Payment{}).Where("HistoryId IN (?) OR ReservationId IN (?)", u.customerStays(u.orm, customer), u.customerReservations(u.orm, customer)).
+		Count(&count).Error
+	return count, err
+}
+
+func (u *CustomerRepository) CountOpenReservationsByCustomer(customer *model.Customer) (int64, error) {
+	var count int64
+	err := u.orm.Model(&model.Reservation{}).Where("CustomerId = ? AND Status IN ?", customer.Id,
+		[]string{model.ReservationTentative, model.ReservationConfirmed, model.ReservationCheckedIn}).Count(&count).Error
+	return count, err
+}
+
+func (u *CustomerRepository) CountInvoicesByCustomer(customer *model.Customer) (int64, error) {
+	var count int64
+	stays := u.orm.Model(&model.History{}).Select("Id").Where("CustomerId = ?", customer.Id)
+	groups := u.orm.Model(&model.History{}).Select("GroupId").Where("CustomerId = ? AND GroupId IS NOT NULL", customer.Id)
+	err := u.orm.Model(&model.Invoice{}).Where("CustomerId = ? OR HistoryId IN (?) OR GroupId IN (?)", customer.Id, stays, groups).Count(&count).Error
+	return count, err
+}
+
+func (u *CustomerRepository) AnonymizeCustomer(customer *model.Customer, log *model.AuditLog) error {
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Customer{}).Where("Id = ?", customer.Id).Updates(map[string]interface{}{
+			"Name":         customer.Name,
+			"Birthday":     customer.Birthday,
+			"NationalId":   customer.NationalId,
+			"Address":      "",
+			"PhoneNumber":  "",
+			"CarNumber":    "",
+			"Note":         "",
+			"AnonymizedAt": customer.AnonymizedAt,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.History{}).Where("CustomerId = ?", customer.Id).Update("Note", "").Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Reservation{}).Where("CustomerId = ?", customer.Id).Update("Note", "").Error; err != nil {
+			return err
+		}
+		return tx.Create(log).Error
+	})
+}
+
+func (u *CustomerRepository) ListReservationsByCustomer(customer *model.Customer) ([]*model.Reservation, error) {
+	var reservations []*model.Reservation
+	err := u.orm.Preload("Room").Where("CustomerId = ?", customer.Id).Order("CheckIn").Find(&reservations).Error
+	return reservations, err
+}
+
+func (u *CustomerRepository) ListPaymentsByCustomer(customer *model.Customer) ([]*model.Payment, error) {
+	var payments []*model.Payment
+	err := u.orm.Where("HistoryId IN (?) OR ReservationId IN (?)", u.customerStays(u.orm, customer), u.customerReservations(u.orm, customer)).
+		Order("PaidAt").Find(&payments).Error
+	return payments, err
+}
+
+func (u *CustomerRepository) ListCompanionStays(customer *model.Customer) ([]*model.History, error) {
+	var histories []*model.History
+	guests := u.orm.Model(&model.StayGuest{}).Select("HistoryId").Where("CustomerId = ?", customer.Id)
+	err := u.orm.Preload("Room").Where("Id IN (?) AND CustomerId <> ?", guests, customer.Id).Order("CheckIn").Find(&histories).Error
+	return histories, err
+}
+
+func (u *CustomerRepository) ListInvoicesByCustomer(customer *model.Customer) ([]*model.Invoice, error) {
+	var invoices []*model.Invoice
+	err := u.orm.Preload("Lines", orderLines).Where("CustomerId = ?", customer.Id).Order("IssuedAt").Find(&invoices).Error
+	return invoices, err
+}
+
+func (u *CustomerRepository) ListRegistrationSubmissionsByCustomer(customer *model.Customer) ([]*model.RegistrationSubmission, error) {
+	var submissions []*model.RegistrationSubmission
+	err := u.orm.Where("CustomerId = ?", customer.Id).Order("SubmittedAt").Find(&submissions).Error
+	return submissions, err
+}
+
+func (u *CustomerRepository) ListAuditLogs(log *model.AuditLog) ([]*model.AuditLog, error) {
+	var logs []*model.AuditLog
+	err := u.orm.Where("Entity = ? AND EntityId = ?", log.Entity, log.EntityId).Order("CreatedAt").Find(&logs).Error
+	return logs, err
+}
+
+func (u *CustomerRepository) CreateAuditLog(log *model.AuditLog) error {
+	return u.orm.Create(log).Error
+}
+
+func (u *CustomerRepository) ListCustomersByNationalIds(nationalIds []string) ([]*model.Customer, error) {
+	var customers []*model.Customer
+	if len(nationalIds) == 0 {
+		return customers, nil
+	}
+	err := u.orm.Where("NationalId IN ?", nationalIds).Find(&customers).Error
+	return customers, err
+}
+
+func (u *CustomerRepository) UpsertCustomers(customers []*model.Customer) error {
+	if len(customers) == 0 {
+		return nil
+	}
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		return tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "NationalId"}},
+			DoUpdates: clause.AssignmentColumns([]string{"Name", "Gender", "Birthday", "Address", "PhoneNumber", "CarNumber", "CitizenshipId", "Note"}),
+		}).CreateInBatches(&customers, importBatchSize).Error
+	})
+}
+
+func (u *CustomerRepository) ListCitizenships() ([]*model.Citizenship, error) {
+	var citizenships []*model.Citizenship
+	err := u.orm.Find(&citizenships).Error
+	return citizenships, err
+}
+
+func (u *CustomerRepository) StreamCustomers(filter *dto.CustomerFilter, fn func(customer *model.Customer) error) error {
+	var customers []*model.Customer
+	query := u.orm.Preload("Citizenship")
+	if filter.Name != "" {
+		query = query.Where("Name LIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.PhoneNumber != "" {
+		query = query.Where("PhoneNumber LIKE ?", "%"+filter.PhoneNumber+"%")
+	}
+	if filter.NationalId != "" {
+		query = query.Where("NationalId = ?", filter.NationalId)
+	}
+	if filter.Citizenship != 0 {
+		query = query.Where("CitizenshipId = ?", filter.Citizenship)
+	}
+	return query.FindInBatches(&customers, streamBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, customer := range customers {
+			if err := fn(customer); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// customerStays selects the Ids of the Histories of the customer
+func (u *CustomerRepository) customerStays(tx *gorm.DB, customer *model.Customer) *gorm.DB {
+	return tx.Model(&model.History{}).Select("Id").Where("CustomerId = ?", customer.Id)
+}
+
+// customerReservations selects the Ids of the Reservations of the customer
+func (u *CustomerRepository) customerReservations(tx *gorm.DB, customer *model.Customer) *gorm.DB {
+	return tx.Model(&model.Reservation{}).Select("Id").Where("CustomerId = ?", customer.Id)
+}
+
+func orderLines(db *gorm.DB) *gorm.DB {
+	return db.Order("Position")
 }
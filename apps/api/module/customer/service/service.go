@@ -4,7 +4,11 @@ import (
 	"errors"
 	"github.com/S1nceU/CRMS/apps/api/domain"
 	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
 	"github.com/google/uuid"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type CustomerService struct {
@@ -149,15 +153,234 @@ func (u *CustomerService) DeleteCustomer(customerId uuid.UUID) error {
 	newCustomer := &model.Customer{
 		Id: customerId,
 	}
+	var count int64
 	if _, err = u.GetCustomerByCustomerId(newCustomer.Id); err != nil {
 		return err
 	}
+	if count, err = u.repo.CountPaymentsByCustomer(newCustomer); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This customer has payments and cannot be deleted")
+	}
+	if count, err = u.repo.CountInvoicesByCustomer(newCustomer); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This customer has invoices and cannot be deleted")
+	}
+	if count, err = u.repo.CountOpenReservationsByCustomer(newCustomer); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This customer has open reservations and cannot be deleted")
+	}
 	if err = u.repo.DeleteCustomer(newCustomer); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (u *CustomerService) ExportCustomerData(customerId uuid.UUID) (*dto.CustomerDataPackage, error) {
+	var err error
+	var customer *model.Customer
+	var logs []*model.AuditLog
+
+	if customer, err = u.GetCustomerByCustomerId(customerId); err != nil {
+		return nil, err
+	}
+
+	stays := customer.Histories
+	customer.Histories = nil
+	data := &dto.CustomerDataPackage{
+		Profile: customer,
+		Contacts: dto.CustomerContacts{
+			Address:     customer.Address,
+			PhoneNumber: customer.PhoneNumber,
+			CarNumber:   customer.CarNumber,
+		},
+		Stays: stays,
+	}
+	if data.CompanionStays, err = u.repo.ListCompanionStays(customer); err != nil {
+		return nil, err
+	}
+	if data.Reservations, err = u.repo.ListReservationsByCustomer(customer); err != nil {
+		return nil, err
+	}
+	if data.Payments, err = u.repo.ListPaymentsByCustomer(customer); err != nil {
+		return nil, err
+	}
+	if data.Invoices, err = u.repo.ListInvoicesByCustomer(customer); err != nil {
+		return nil, err
+	}
+	if data.RegistrationSubmissions, err = u.repo.ListRegistrationSubmissionsByCustomer(customer); err != nil {
+		return nil, err
+	}
+
+	// The export is only logged once the package is assembled, so a failed export leaves no trace of a disclosure
+	exportLog := newCustomerAuditLog(customer.Id, "export", "")
+	if err = u.repo.CreateAuditLog(exportLog); err != nil {
+		return nil, err
+	}
+	if logs, err = u.repo.ListAuditLogs(exportLog); err != nil {
+		return nil, err
+	}
+	data.GeneratedAt = exportLog.CreatedAt
+	data.AuditTrail = logs
+	return data, err
+}
+
+// EraseCustomer anonymizes the identifying fields of a customer and clears the notes of their stays and reservations.
+// Stays and their prices are kept for accounting, and payments are ledger entries that are never changed.
+// Issued invoices are legal documents and keep the name printed on them.
+func (u *CustomerService) EraseCustomer(customerId uuid.UUID, reason string) error {
+	var err error
+	var customer *model.Customer
+
+	if customer, err = u.GetCustomerByCustomerId(customerId); err != nil {
+		return err
+	}
+	if customer.AnonymizedAt != nil {
+		return errors.New("error CRMS : This customer is already anonymized")
+	}
+
+	erasedAt := time.Now()
+	customer.Name = "Anonymized"
+	customer.Birthday = time.Date(customer.Birthday.Year(), time.January, 1, 0, 0, 0, 0, customer.Birthday.Location())
+	customer.NationalId = "ERASED-" + customer.Id.String()
+	customer.AnonymizedAt = &erasedAt
+
+	return u.repo.AnonymizeCustomer(customer, newCustomerAuditLog(customer.Id, "erase", reason))
+}
+
+func (u *CustomerService) ImportCustomers(rows []*dto.CustomerImportRow, dryRun bool) (*dto.CustomerImportResult, error) {
+	var err error
+	var citizenships []*model.Citizenship
+	var existing []*model.Customer
+
+	if citizenships, err = u.repo.ListCitizenships(); err != nil {
+		return nil, err
+	}
+	citizenshipIndex := map[string]int{}
+	for _, citizenship := range citizenships {
+		citizenshipIndex[strconv.Itoa(citizenship.Id)] = citizenship.Id
+		citizenshipIndex[strings.ToLower(citizenship.Nation)] = citizenship.Id
+		citizenshipIndex[strings.ToLower(citizenship.Alpha3)] = citizenship.Id
+	}
+
+	var nationalIds []string
+	for _, row := range rows {
+		nationalIds = append(nationalIds, strings.TrimSpace(row.Customer.NationalId))
+	}
+	if existing, err = u.repo.ListCustomersByNationalIds(nationalIds); err != nil {
+		return nil, err
+	}
+	existingIds := map[string]uuid.UUID{}
+	for _, customer := range existing {
+		existingIds[customer.NationalId] = customer.Id
+	}
+
+	result := &dto.CustomerImportResult{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Errors: []*dto.CustomerImportError{},
+	}
+	var valid []*model.Customer
+	seen := map[string]int{}
+	for _, row := range rows {
+		customer, rowErr := transformImportRow(row, citizenshipIndex)
+		if rowErr == nil {
+			rowErr = validateCustomerInfo(customer)
+		}
+		if rowErr == nil {
+			if firstRow, ok := seen[customer.NationalId]; ok {
+				rowErr = errors.New("error CRMS : Duplicated NationalId with row " + strconv.Itoa(firstRow))
+			}
+		}
+		if rowErr != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, &dto.CustomerImportError{
+				Row:        row.Row,
+				NationalId: row.Customer.NationalId,
+				Message:    rowErr.Error(),
+			})
+			continue
+		}
+		seen[customer.NationalId] = row.Row
+		if id, ok := existingIds[customer.NationalId]; ok {
+			customer.Id = id
+			result.Updated++
+		} else {
+			customer.Id = uuid.New()
+			result.Created++
+		}
+		valid = append(valid, customer)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err = u.repo.UpsertCustomers(valid); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (u *CustomerService) StreamCustomers(filter *dto.CustomerFilter, fn func(customer *model.Customer) error) error {
+	return u.repo.StreamCustomers(filter, fn)
+}
+
+func transformImportRow(row *dto.CustomerImportRow, citizenshipIndex map[string]int) (*model.Customer, error) {
+	birthday, err := parseImportDate(strings.TrimSpace(row.Customer.Birthday))
+	if err != nil {
+		return nil, errors.New("error CRMS : Birthday is invalid")
+	}
+	gender := strings.TrimSpace(row.Customer.Gender)
+	if strings.EqualFold(gender, "Male") || strings.EqualFold(gender, "M") {
+		gender = "Male"
+	} else if strings.EqualFold(gender, "Female") || strings.EqualFold(gender, "F") {
+		gender = "Female"
+	}
+	citizenshipId := row.Customer.Citizenship
+	if row.Citizenship != "" {
+		var ok bool
+		if citizenshipId, ok = citizenshipIndex[strings.ToLower(strings.TrimSpace(row.Citizenship))]; !ok {
+			return nil, errors.New("error CRMS : There is no this citizenship")
+		}
+	}
+	return &model.Customer{
+		Name:          strings.TrimSpace(row.Customer.Name),
+		Gender:        gender,
+		Birthday:      birthday,
+		NationalId:    strings.TrimSpace(row.Customer.NationalId),
+		Address:       strings.TrimSpace(row.Customer.Address),
+		PhoneNumber:   strings.TrimSpace(row.Customer.PhoneNumber),
+		CarNumber:     strings.TrimSpace(row.Customer.CarNumber),
+		CitizenshipId: citizenshipId,
+		Note:          strings.TrimSpace(row.Customer.Note),
+	}, nil
+}
+
+// parseImportDate accepts the date layouts spreadsheets commonly produce
+func parseImportDate(in string) (time.Time, error) {
+	var err error
+	var date time.Time
+	for _, layout := range []string{"2006-01-02", "2006/01/02", "2006/1/2", "01-02-06"} {
+		if date, err = time.ParseInLocation(layout, in, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return date, err
+}
+
+func newCustomerAuditLog(customerId uuid.UUID, action string, detail string) *model.AuditLog {
+	return &model.AuditLog{
+		Id:        uuid.New(),
+		Entity:    "customer",
+		EntityId:  customerId.String(),
+		Action:    action,
+		Detail:    detail,
+		CreatedAt: time.Now(),
+	}
+}
+
 func convertToSliceOfCustomer(customers []*model.Customer) []*model.Customer {
 	var customersSlice []*model.Customer
 	for _, customer := range customers {
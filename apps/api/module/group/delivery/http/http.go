@@ -0,0 +1,288 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+)
+
+type BookingGroupHandler struct {
+	ser domain.BookingGroupService
+}
+
+func NewBookingGroupHandler(e *gin.Engine, ser domain.BookingGroupService) {
+	handler := &BookingGroupHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/groupList", handler.ListGroups)
+		api.POST("/groupId", handler.GetGroupById)
+		api.POST("/groupCre", handler.CreateGroup)
+		api.POST("/groupMod", handler.ModifyGroup)
+		api.POST("/groupDel", handler.DeleteGroup)
+		api.POST("/groupStays", handler.ListGroupStays)
+		api.POST("/groupConfirm", handler.ConfirmGroup)
+		api.POST("/groupCancel", handler.CancelGroup)
+		api.POST("/groupCheckIn", handler.CheckInGroup)
+		api.POST("/groupMove", handler.MoveGroupDates)
+	}
+}
+
+// ListGroups @Summary ListGroups
+// @Description Get all BookingGroups, newest first
+// @Tags BookingGroup
+// @Produce application/json
+// @Success 200 {object} []model.BookingGroup
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupList [post]
+func (u *BookingGroupHandler) ListGroups(c *gin.Context) {
+	groups, err := u.ser.ListGroups()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List all booking groups",
+		"groups":  groups,
+	})
+}
+
+// GetGroupById @Summary GetGroupById
+// @Description Get BookingGroup by GroupId
+// @Tags BookingGroup
+// @Produce application/json
+// @Param GroupId body dto.GroupIdRequest true "BookingGroup id"
+// @Success 200 {object} model.BookingGroup
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupId [post]
+func (u *BookingGroupHandler) GetGroupById(c *gin.Context) {
+	request := dto.GroupIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	group, err := u.ser.GetGroupById(request.GroupId)
+	if err != nil {
+		u.respondGroupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// CreateGroup @Summary CreateGroup
+// @Description Create a new BookingGroup. Reservations and stays join it through their GroupId
+// @Tags BookingGroup
+// @Accept json
+// @Produce application/json
+// @Param BookingGroup body dto.GroupRequest true "BookingGroup Information" example: {"Name": "Wedding party", "OrganizerId": "00000000-0000-0000-0000-000000000000", "Note": ""}
+// @Success 200 {object} model.BookingGroup
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupCre [post]
+func (u *BookingGroupHandler) CreateGroup(c *gin.Context) {
+	request := dto.GroupRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	group, err := u.ser.CreateGroup(transformToGroup(request))
+	if err != nil {
+		u.respondGroupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// ModifyGroup @Summary ModifyGroup
+// @Description Modify BookingGroup data
+// @Tags BookingGroup
+// @Accept json
+// @Produce application/json
+// @Param BookingGroup body dto.GroupRequest true "BookingGroup Information"
+// @Success 200 {object} model.BookingGroup
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupMod [post]
+func (u *BookingGroupHandler) ModifyGroup(c *gin.Context) {
+	request := dto.GroupRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	group, err := u.ser.UpdateGroup(transformToGroup(request))
+	if err != nil {
+		u.respondGroupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup @Summary DeleteGroup
+// @Description Delete a BookingGroup that has no reservations or stays left
+// @Tags BookingGroup
+// @Accept json
+// @Produce application/json
+// @Param GroupId body dto.GroupIdRequest true "BookingGroup id"
+// @Success 200 {object} string "Message": "Delete success"
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupDel [post]
+func (u *BookingGroupHandler) DeleteGroup(c *gin.Context) {
+	request := dto.GroupIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	if err := u.ser.DeleteGroup(request.GroupId); err != nil {
+		u.respondGroupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "Delete success",
+	})
+}
+
+// ListGroupStays @Summary ListGroupStays
+// @Description Get a BookingGroup with all its reservations and stays
+// @Tags BookingGroup
+// @Produce application/json
+// @Param GroupId body dto.GroupIdRequest true "BookingGroup id"
+// @Success 200 {object} dto.GroupStays
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupStays [post]
+func (u *BookingGroupHandler) ListGroupStays(c *gin.Context) {
+	request := dto.GroupIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	stays, err := u.ser.ListGroupStays(request.GroupId)
+	if err != nil {
+		u.respondGroupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stays)
+}
+
+// ConfirmGroup @Summary ConfirmGroup
+// @Description Confirm every tentative reservation of a BookingGroup. Reservations that fail are listed with the reason and left as they were
+// @Tags BookingGroup
+// @Produce application/json
+// @Param GroupId body dto.GroupIdRequest true "BookingGroup id"
+// @Success 200 {object} dto.GroupOperationResult
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupConfirm [post]
+func (u *BookingGroupHandler) ConfirmGroup(c *gin.Context) {
+	u.runGroupOperation(c, u.ser.ConfirmGroup)
+}
+
+// CancelGroup @Summary CancelGroup
+// @Description Cancel every tentative or confirmed reservation of a BookingGroup, charging the fee of each reservation policy
+// @Tags BookingGroup
+// @Produce application/json
+// @Param GroupId body dto.GroupIdRequest true "BookingGroup id"
+// @Success 200 {object} dto.GroupOperationResult
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupCancel [post]
+func (u *BookingGroupHandler) CancelGroup(c *gin.Context) {
+	u.runGroupOperation(c, u.ser.CancelGroup)
+}
+
+// CheckInGroup @Summary CheckInGroup
+// @Description Check in every confirmed reservation of a BookingGroup. Reservations that fail are listed with the reason and left as they were
+// @Tags BookingGroup
+// @Produce application/json
+// @Param GroupId body dto.GroupIdRequest true "BookingGroup id"
+// @Success 200 {object} dto.GroupOperationResult
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupCheckIn [post]
+func (u *BookingGroupHandler) CheckInGroup(c *gin.Context) {
+	u.runGroupOperation(c, u.ser.CheckInGroup)
+}
+
+// MoveGroupDates @Summary MoveGroupDates
+// @Description Shift the open reservations of a BookingGroup so the earliest checks in on CheckIn. Nothing moves when a room is taken
+// @Tags BookingGroup
+// @Accept json
+// @Produce application/json
+// @Param Move body dto.GroupMoveRequest true "Group id and new check-in date" example: {"GroupId": "00000000-0000-0000-0000-000000000000", "CheckIn": "2020-01-01"}
+// @Success 200 {object} []model.Reservation
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /groupMove [post]
+func (u *BookingGroupHandler) MoveGroupDates(c *gin.Context) {
+	request := dto.GroupMoveRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	reservations, err := u.ser.MoveGroupDates(request.GroupId, request.CheckIn)
+	if err != nil {
+		u.respondGroupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":      "Move group dates",
+		"reservations": reservations,
+	})
+}
+
+func (u *BookingGroupHandler) runGroupOperation(c *gin.Context, operation func(groupId uuid.UUID) (*dto.GroupOperationResult, error)) {
+	request := dto.GroupIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	result, err := operation(request.GroupId)
+	if err != nil {
+		u.respondGroupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (u *BookingGroupHandler) respondGroupError(c *gin.Context, err error) {
+	switch {
+	case strings.HasPrefix(err.Error(), "error CRMS : Room is already booked"),
+		err.Error() == "error CRMS : There is no this booking group",
+		err.Error() == "error CRMS : Booking group Info is incomplete",
+		err.Error() == "error CRMS : There is no this customer",
+		err.Error() == "error CRMS : This group still has reservations or stays",
+		err.Error() == "error CRMS : This group has no open reservations",
+		err.Error() == "error CRMS : Date is incomplete",
+		err.Error() == "error CRMS : Check-in date is before today":
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+	}
+}
+
+func transformToGroup(requestData dto.GroupRequest) *model.BookingGroup {
+	return &model.BookingGroup{
+		Id:          requestData.GroupId,
+		Name:        requestData.Name,
+		OrganizerId: requestData.OrganizerId,
+		Note:        requestData.Note,
+	}
+}
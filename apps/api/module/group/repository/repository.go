@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/module/internal/stay"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookingGroupRepository struct {
+	orm *gorm.DB
+}
+
+func NewBookingGroupRepository(orm *gorm.DB) domain.BookingGroupRepository {
+	return &BookingGroupRepository{
+		orm: orm,
+	}
+}
+
+func (u *BookingGroupRepository) ListGroups() ([]*model.BookingGroup, error) {
+	var groups []*model.BookingGroup
+	err := u.orm.Preload("Organizer").Order("CreatedAt DESC").Find(&groups).Error
+	return groups, err
+}
+
+func (u *BookingGroupRepository) GetGroupById(group *model.BookingGroup) (*model.BookingGroup, error) {
+	err := u.orm.Preload("Organizer").Where("Id = ?", group.Id).Find(&group).Error
+	return group, err
+}
+
+func (u *BookingGroupRepository) CreateGroup(group *model.BookingGroup) (*model.BookingGroup, error) {
+	if err := u.orm.Omit(clause.Associations).Create(&group).Error; err != nil {
+		return nil, err
+	}
+	return u.GetGroupById(group)
+}
+
+func (u *BookingGroupRepository) UpdateGroup(group *model.BookingGroup) (*model.BookingGroup, error) {
+	if err := u.orm.Model(group).Select("Name", "OrganizerId", "Note").Where("Id = ?", group.Id).Updates(&group).Error; err != nil {
+		return nil, err
+	}
+	return u.GetGroupById(group)
+}
+
+func (u *BookingGroupRepository) DeleteGroup(group *model.BookingGroup) error {
+	return u.orm.Where("Id = ?", group.Id).Delete(&model.BookingGroup{}).Error
+}
+
+func (u *BookingGroupRepository) ListReservationsByGroup(reservation *model.Reservation) ([]*model.Reservation, error) {
+	var reservations []*model.Reservation
+	err := u.orm.Preload("Room").Where("GroupId = ?", reservation.GroupId).Order("CheckIn").Find(&reservations).Error
+	return reservations, err
+}
+
+func (u *BookingGroupRepository) ListHistoriesByGroup(history *model.History) ([]*model.History, error) {
+	var histories []*model.History
+	err := u.orm.Preload("Room").Preload("TaxLines").Preload("Guests").Where("GroupId = ?", history.GroupId).Order("CheckIn").Find(&histories).Error
+	return histories, err
+}
+
+// MoveReservations saves the new dates of reservations in one transaction, so the group moves as a whole or not at
+// all. The reservations may take each other's rooms and nights, so none of them counts as a conflict.
+func (u *BookingGroupRepository) MoveReservations(reservations []*model.Reservation) error {
+	var ids []uuid.UUID
+	for _, reservation := range reservations {
+		ids = append(ids, reservation.Id)
+	}
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		for _, reservation := range reservations {
+			if err := stay.CheckRoomAvailability(tx, reservation.RoomId, reservation.CheckIn, reservation.CheckOut, ids...); err != nil {
+				return err
+			}
+			if err := tx.Model(reservation).Where("Id = ?", reservation.Id).Updates(map[string]interface{}{
+				"CheckIn":  reservation.CheckIn,
+				"CheckOut": reservation.CheckOut,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (u *BookingGroupRepository) ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error) {
+	err := u.orm.Where("Id = ?", customer.Id).Find(&customer).Error
+	return customer, err
+}
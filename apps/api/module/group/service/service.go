@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+type BookingGroupService struct {
+	repo           domain.BookingGroupRepository
+	reservationSer domain.ReservationService
+}
+
+func NewBookingGroupService(repo domain.BookingGroupRepository, reservationSer domain.ReservationService) domain.BookingGroupService {
+	return &BookingGroupService{
+		repo:           repo,
+		reservationSer: reservationSer,
+	}
+}
+
+func (u *BookingGroupService) ListGroups() ([]*model.BookingGroup, error) {
+	var err error
+	var groups []*model.BookingGroup
+	if groups, err = u.repo.ListGroups(); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfGroup(groups), err
+}
+
+func (u *BookingGroupService) GetGroupById(groupId uuid.UUID) (*model.BookingGroup, error) {
+	var err error
+	newGroup := &model.BookingGroup{
+		Id: groupId,
+	}
+	if newGroup, err = u.repo.GetGroupById(newGroup); err != nil {
+		return nil, err
+	} else if newGroup.Name == "" {
+		return nil, errors.New("error CRMS : There is no this booking group")
+	}
+	return newGroup, err
+}
+
+func (u *BookingGroupService) CreateGroup(group *model.BookingGroup) (*model.BookingGroup, error) {
+	var err error
+	if err = u.validateGroupInfo(group); err != nil {
+		return nil, err
+	}
+	group.Id = uuid.New()
+	return u.repo.CreateGroup(group)
+}
+
+func (u *BookingGroupService) UpdateGroup(group *model.BookingGroup) (*model.BookingGroup, error) {
+	var err error
+	if _, err = u.GetGroupById(group.Id); err != nil {
+		return nil, err
+	}
+	if err = u.validateGroupInfo(group); err != nil {
+		return nil, err
+	}
+	return u.repo.UpdateGroup(group)
+}
+
+func (u *BookingGroupService) DeleteGroup(groupId uuid.UUID) error {
+	var err error
+	var stays *dto.GroupStays
+	if stays, err = u.ListGroupStays(groupId); err != nil {
+		return err
+	}
+	if len(stays.Reservations) != 0 || len(stays.Histories) != 0 {
+		return errors.New("error CRMS : This group still has reservations or stays")
+	}
+	return u.repo.DeleteGroup(stays.Group)
+}
+
+func (u *BookingGroupService) ListGroupStays(groupId uuid.UUID) (*dto.GroupStays, error) {
+	var err error
+	stays := &dto.GroupStays{}
+	if stays.Group, err = u.GetGroupById(groupId); err != nil {
+		return nil, err
+	}
+	if stays.Reservations, err = u.repo.ListReservationsByGroup(&model.Reservation{GroupId: &groupId}); err != nil {
+		return nil, err
+	}
+	if stays.Histories, err = u.repo.ListHistoriesByGroup(&model.History{GroupId: &groupId}); err != nil {
+		return nil, err
+	}
+	return stays, nil
+}
+
+func (u *BookingGroupService) ConfirmGroup(groupId uuid.UUID) (*dto.GroupOperationResult, error) {
+	return u.eachReservation(groupId, []string{model.ReservationTentative}, func(reservation *model.Reservation) (*model.Reservation, error) {
+		return u.reservationSer.ChangeReservationStatus(reservation.Id, model.ReservationConfirmed)
+	})
+}
+
+func (u *BookingGroupService) CancelGroup(groupId uuid.UUID) (*dto.GroupOperationResult, error) {
+	return u.eachReservation(groupId, []string{model.ReservationTentative, model.ReservationConfirmed}, func(reservation *model.Reservation) (*model.Reservation, error) {
+		return u.reservationSer.ChangeReservationStatus(reservation.Id, model.ReservationCancelled)
+	})
+}
+
+func (u *BookingGroupService) CheckInGroup(groupId uuid.UUID) (*dto.GroupOperationResult, error) {
+	return u.eachReservation(groupId, []string{model.ReservationConfirmed}, func(reservation *model.Reservation) (*model.Reservation, error) {
+		return u.reservationSer.CheckInReservation(reservation.Id)
+	})
+}
+
+// MoveGroupDates shifts every open reservation of the group by the same number of days, so the earliest one checks
+// in on checkIn and the group keeps its shape. Either every reservation moves or none does.
+func (u *BookingGroupService) MoveGroupDates(groupId uuid.UUID, checkIn string) ([]*model.Reservation, error) {
+	var err error
+	var date time.Time
+	var reservations []*model.Reservation
+	if date, err = time.ParseInLocation("2006-01-02", checkIn, time.Local); err != nil {
+		return nil, errors.New("error CRMS : Date is incomplete")
+	}
+	if date.Before(today()) {
+		return nil, errors.New("error CRMS : Check-in date is before today")
+	}
+	if reservations, err = u.openReservations(groupId, []string{model.ReservationTentative, model.ReservationConfirmed}); err != nil {
+		return nil, err
+	} else if len(reservations) == 0 {
+		return nil, errors.New("error CRMS : This group has no open reservations")
+	}
+
+	earliest := reservations[0].CheckIn
+	for _, reservation := range reservations {
+		if reservation.CheckIn.Before(earliest) {
+			earliest = reservation.CheckIn
+		}
+	}
+	// Counted on the calendar, a DST change in between would make the difference one hour short of whole days
+	days := model.NightsBetween(earliest, date)
+	if days == 0 {
+		return reservations, nil
+	}
+	for _, reservation := range reservations {
+		reservation.CheckIn = reservation.CheckIn.AddDate(0, 0, days)
+		reservation.CheckOut = reservation.CheckOut.AddDate(0, 0, days)
+	}
+	if err = u.repo.MoveReservations(reservations); err != nil {
+		return nil, err
+	}
+	return u.openReservations(groupId, []string{model.ReservationTentative, model.ReservationConfirmed})
+}
+
+// eachReservation runs fn on every reservation of the group in one of statuses. A reservation that fails is reported
+// and left as it was, the others go on.
+func (u *BookingGroupService) eachReservation(groupId uuid.UUID, statuses []string, fn func(reservation *model.Reservation) (*model.Reservation, error)) (*dto.GroupOperationResult, error) {
+	var err error
+	var reservations []*model.Reservation
+	if reservations, err = u.openReservations(groupId, statuses); err != nil {
+		return nil, err
+	}
+	result := &dto.GroupOperationResult{}
+	for _, reservation := range reservations {
+		updated, err := fn(reservation)
+		if err != nil {
+			result.Failed = append(result.Failed, &dto.GroupOperationError{
+				ReservationId: reservation.Id,
+				Room:          reservation.Room.Number,
+				Message:       err.Error(),
+			})
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, updated)
+	}
+	return result, nil
+}
+
+func (u *BookingGroupService) openReservations(groupId uuid.UUID, statuses []string) ([]*model.Reservation, error) {
+	var err error
+	var reservations []*model.Reservation
+	var selected []*model.Reservation
+	if _, err = u.GetGroupById(groupId); err != nil {
+		return nil, err
+	}
+	if reservations, err = u.repo.ListReservationsByGroup(&model.Reservation{GroupId: &groupId}); err != nil {
+		return nil, err
+	}
+	for _, reservation := range reservations {
+		for _, status := range statuses {
+			if reservation.Status == status {
+				selected = append(selected, reservation)
+				break
+			}
+		}
+	}
+	return selected, nil
+}
+
+func (u *BookingGroupService) validateGroupInfo(group *model.BookingGroup) error {
+	var err error
+	if group.Name == "" || group.OrganizerId == uuid.Nil {
+		return errors.New("error CRMS : Booking group Info is incomplete")
+	}
+	organizer := &model.Customer{
+		Id: group.OrganizerId,
+	}
+	if organizer, err = u.repo.ConfirmCustomerExistence(organizer); err != nil {
+		return err
+	} else if organizer.Name == "" {
+		return errors.New("error CRMS : There is no this customer")
+	}
+	return nil
+}
+
+func convertToSliceOfGroup(groups []*model.BookingGroup) []*model.BookingGroup {
+	var groupsSlice []*model.BookingGroup
+	for _, group := range groups {
+		groupsSlice = append(groupsSlice, group)
+	}
+	return groupsSlice
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/S1nceU/CRMS/apps/api/sheet"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"time"
+)
+
+var historyColumns = []sheet.Column[*model.History]{
+	{Key: "Id", Labels: map[string]string{"en": "Id", "zh-TW": "編號"}, Value: func(h *model.History) interface{} { return h.Id.String() }},
+	{Key: "CustomerId", Labels: map[string]string{"en": "Customer Id", "zh-TW": "顧客編號"}, Value: func(h *model.History) interface{} { return h.CustomerId.String() }},
+	{Key: "Kind", Labels: map[string]string{"en": "Kind", "zh-TW": "類型"}, Value: func(h *model.History) interface{} { return h.Kind }},
+	{Key: "CheckIn", Labels: map[string]string{"en": "Check-in", "zh-TW": "入住日期"}, Value: func(h *model.History) interface{} { return h.CheckIn }},
+	{Key: "CheckOut", Labels: map[string]string{"en": "Check-out", "zh-TW": "退房日期"}, Value: func(h *model.History) interface{} { return h.CheckOut }},
+	{Key: "Nights", Labels: map[string]string{"en": "Nights", "zh-TW": "晚數"}, Value: func(h *model.History) interface{} { return h.Nights() }},
+	{Key: "NumberOfPeople", Labels: map[string]string{"en": "Number Of People", "zh-TW": "人數"}, Value: func(h *model.History) interface{} { return h.NumberOfPeople }},
+	{Key: "Price", Labels: map[string]string{"en": "Price", "zh-TW": "價格"}, Value: func(h *model.History) interface{} { return h.Price }},
+	{Key: "Currency", Labels: map[string]string{"en": "Currency", "zh-TW": "幣別"}, Value: func(h *model.History) interface{} { return h.Currency }},
+	{Key: "RoomCharge", Labels: map[string]string{"en": "Room Charge", "zh-TW": "房費"}, Value: func(h *model.History) interface{} { return decimal(h, h.RoomCharge) }},
+	{Key: "ExtraCharge", Labels: map[string]string{"en": "Extra Charge", "zh-TW": "額外費用"}, Value: func(h *model.History) interface{} { return decimal(h, h.ExtraCharge) }},
+	{Key: "Discount", Labels: map[string]string{"en": "Discount", "zh-TW": "折扣"}, Value: func(h *model.History) interface{} { return decimal(h, h.Discount) }},
+	{Key: "TaxAmount", Labels: map[string]string{"en": "Tax", "zh-TW": "稅額"}, Value: func(h *model.History) interface{} { return decimal(h, h.TaxAmount) }},
+	{Key: "TotalAmount", Labels: map[string]string{"en": "Total", "zh-TW": "總額"}, Value: func(h *model.History) interface{} { return decimal(h, h.TotalAmount) }},
+	{Key: "Room", Labels: map[string]string{"en": "Room", "zh-TW": "房號"}, Value: func(h *model.History) interface{} { return h.Room.Number }},
+	{Key: "Note", Labels: map[string]string{"en": "Note", "zh-TW": "備註"}, Value: func(h *model.History) interface{} { return h.Note }},
+}
+
+// ExportHistories @Summary ExportHistories
+// @Description Download the Histories matching the filter as CSV, XLSX or JSON Lines
+// @Tags History
+// @Accept json
+// @Produce application/octet-stream
+// @Param Export body dto.HistoryExportRequest true "Format, columns, header language and filter" example: {"Format": "xlsx", "Language": "en", "startDate": "2020-01-01", "endDate": "2020-01-31"}
+// @Success 200 {file} file
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /historyListExport [post]
+func (u *HistoryHandler) ExportHistories(c *gin.Context) {
+	request := dto.HistoryExportRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	contentType, extension, err := sheet.ContentType(request.Format)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	columns, err := sheet.SelectColumns(historyColumns, request.Columns)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	keys, labels := sheet.Header(columns, request.Language)
+
+	var writer sheet.Writer
+	open := func() error {
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", "attachment; filename=histories-"+time.Now().Format("20060102")+extension)
+		c.Status(http.StatusOK)
+		if writer, err = sheet.NewWriter(request.Format, c.Writer); err != nil {
+			return err
+		}
+		return writer.WriteHeader(keys, labels)
+	}
+	err = u.ser.StreamHistories(&request.HistoryFilter, func(history *model.History) error {
+		if writer == nil {
+			if err := open(); err != nil {
+				return err
+			}
+		}
+		return writer.WriteRow(sheet.Values(columns, history))
+	})
+	if err != nil && writer == nil {
+		if err.Error() == "error CRMS : Date is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Start date is after end date" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	if err != nil {
+		log.Println("Export histories failed:", err)
+		return
+	}
+	if writer == nil {
+		if err = open(); err != nil {
+			log.Println("Export histories failed:", err)
+			return
+		}
+	}
+	if err = writer.Close(); err != nil {
+		log.Println("Export histories failed:", err)
+	}
+}
+
+func decimal(h *model.History, amount int64) string {
+	return model.Money{Amount: amount, Currency: h.Currency}.Decimal()
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -24,10 +25,12 @@ func NewHistoryHandler(e *gin.Engine, ser domain.HistoryService) {
 		api.POST("/historyByHistoryId", handler.GetHistoryByHistoryId)
 		api.POST("/historyCre", handler.CreateHistory)
 		api.POST("/historyMod", handler.ModifyHistory)
+		api.POST("/historyMove", handler.MoveHistoryRoom)
 		api.POST("/historyDel", handler.DeleteHistory)
 		api.POST("/historyForDuring", handler.GetHistoryForDuring)
 		api.POST("/historyForDate", handler.GetHistoriesForDate)
 		api.POST("/historyCustomerId", handler.GetHistoryByCustomerId)
+		api.POST("/historyListExport", handler.ExportHistories)
 	}
 
 }
@@ -91,7 +94,7 @@ func (u *HistoryHandler) GetHistoryByHistoryId(c *gin.Context) {
 // @Description Create a new HistoryService
 // @Tags History
 // @Produce application/json
-// @Param HistoryService body model.HistoryRequest true "HistoryService Information" example: {"CustomerId": "00000000-0000-0000-0000-000000000000", "Date": "2020-01-01", "NumberOfPeople": 1, "Price": 1000, "Room": "101", "Note": "test"}
+// @Param HistoryService body model.HistoryRequest true "HistoryService Information" example: {"CustomerId": "00000000-0000-0000-0000-000000000000", "CheckIn": "2020-01-01", "CheckOut": "2020-01-03", "NumberOfPeople": 1, "Currency": "TWD", "RoomCharge": 0, "ExtraCharge": 50000, "Discount": 0, "RatePlanId": "00000000-0000-0000-0000-000000000000", "RoomId": "00000000-0000-0000-0000-000000000000", "Note": "test"}
 // @Success 200 {object} model.History
 // @Failure 500 {string} string "{"Message": err.Error()}"
 // @Router /historyCre [post]
@@ -123,6 +126,71 @@ func (u *HistoryHandler) CreateHistory(c *gin.Context) {
 				"Message": err.Error(),
 			})
 			return
+		} else if err.Error() == "error CRMS : Check-out date must be after check-in date" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This room is not active" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Number of people exceeds room capacity" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this booking group" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Currency is invalid" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Discount exceeds the charges" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if strings.HasPrefix(err.Error(), "error CRMS : Rate plans are priced in") {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this rate plan" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This rate plan is not for this room type" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This rate plan is not active" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no rate plan for this room type" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if strings.HasPrefix(err.Error(), "error CRMS : Room is already booked") {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"Message": err.Error(),
@@ -138,7 +206,7 @@ func (u *HistoryHandler) CreateHistory(c *gin.Context) {
 // @Tags History
 // @Accept json
 // @Produce application/json
-// @Param HistoryService body model.HistoryRequest true "HistoryService Information" example: {"HistoryId": "00000000-0000-0000-0000-000000000000", "CustomerId": "00000000-0000-0000-0000-000000000000", "Date": "2020-01-01", "NumberOfPeople": 1, "Price": 10000, "Room": "101", "Note": "test"}
+// @Param HistoryService body model.HistoryRequest true "HistoryService Information" example: {"HistoryId": "00000000-0000-0000-0000-000000000000", "CustomerId": "00000000-0000-0000-0000-000000000000", "CheckIn": "2020-01-01", "CheckOut": "2020-01-03", "NumberOfPeople": 1, "Price": 10000, "RoomId": "00000000-0000-0000-0000-000000000000", "Note": "test"}
 // @Success 200 {object} model.History
 // @Failure 500 {string} string "{"Message": err.Error()}"
 // @Router /historyMod [post]
@@ -175,6 +243,91 @@ func (u *HistoryHandler) ModifyHistory(c *gin.Context) {
 				"Message": err.Error(),
 			})
 			return
+		} else if err.Error() == "error CRMS : Check-out date must be after check-in date" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This room is not active" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Number of people exceeds room capacity" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this booking group" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Number of people is less than the registered guests" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Only stays can be modified" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This stay moved rooms, use a room move to change its room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : The new dates leave no night in a room of this stay" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Currency is invalid" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Discount exceeds the charges" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if strings.HasPrefix(err.Error(), "error CRMS : Rate plans are priced in") {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this rate plan" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This rate plan is not for this room type" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This rate plan is not active" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no rate plan for this room type" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if strings.HasPrefix(err.Error(), "error CRMS : Room is already booked") {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"Message": err.Error(),
@@ -185,6 +338,89 @@ func (u *HistoryHandler) ModifyHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, modifyHistory)
 }
 
+// MoveHistoryRoom @Summary MoveHistoryRoom
+// @Description Move the guests of a stay to another room from Date until check-out. The stay keeps its Id and charges and lists one segment per room
+// @Tags History
+// @Accept json
+// @Produce application/json
+// @Param Move body dto.HistoryMoveRequest true "History id, new room and move date" example: {"HistoryId": "00000000-0000-0000-0000-000000000000", "RoomId": "00000000-0000-0000-0000-000000000000", "Date": "2020-01-02"}
+// @Success 200 {object} model.History
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /historyMove [post]
+func (u *HistoryHandler) MoveHistoryRoom(c *gin.Context) {
+	request := dto.HistoryMoveRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	room := &model.Room{
+		Id:     request.RoomId,
+		Number: request.Room,
+	}
+	history, err := u.ser.MoveHistoryRoom(request.HistoryId, room, request.Date)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Only stays can be modified" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Date is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Move date must be after check-in and before check-out" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : HistoryService Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This room is not active" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Number of people exceeds room capacity" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : The guests are already in this room on the move date" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if strings.HasPrefix(err.Error(), "error CRMS : Room is already booked") {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, history)
+}
+
 // DeleteHistory @Summary DeleteHistory
 // @Description Delete HistoryService by HistoryId
 // @Tags History
@@ -208,6 +444,16 @@ func (u *HistoryHandler) DeleteHistory(c *gin.Context) {
 				"Message": err.Error(),
 			})
 			return
+		} else if err.Error() == "error CRMS : This history has payments, refund them instead" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This history has invoices and cannot be deleted" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"Message": err.Error(),
@@ -221,7 +467,7 @@ func (u *HistoryHandler) DeleteHistory(c *gin.Context) {
 }
 
 // GetHistoryForDuring @Summary GetHistoryForDuring
-// @Description Get HistoryService For During
+// @Description Get HistoryService overlapping During
 // @Tags History
 // @Produce application/json
 // @Param HistoryService body model.DuringRequest true "HistoryService Information" example: {"startDate": "2020-01-01", "endDate": "2020-01-02"}
@@ -270,7 +516,7 @@ func (u *HistoryHandler) GetHistoryForDuring(c *gin.Context) {
 }
 
 // GetHistoriesForDate @Summary GetHistoriesForDate
-// @Description Get Histories For Date
+// @Description Get Histories staying on the night of Date
 // @Tags History
 // @Produce application/json
 // @Param HistoryService body model.DateRequest true "HistoryService Information" example: {"Date": "2020-01-01"}
@@ -351,20 +597,41 @@ func (u *HistoryHandler) GetHistoryByCustomerId(c *gin.Context) {
 }
 
 func transformToHistory(requestData dto.HistoryRequest) (*model.History, error) {
-	date, err := time.ParseInLocation("2006-01-02", requestData.Date, time.Local)
+	if requestData.CheckIn == "" {
+		requestData.CheckIn = requestData.Date
+	}
+	checkIn, err := time.ParseInLocation("2006-01-02", requestData.CheckIn, time.Local)
 	if err != nil {
 		return nil, err
 	}
+	checkOut := checkIn.AddDate(0, 0, 1)
+	if requestData.CheckOut != "" {
+		if checkOut, err = time.ParseInLocation("2006-01-02", requestData.CheckOut, time.Local); err != nil {
+			return nil, err
+		}
+	}
 	h := &model.History{
 		CustomerId:     requestData.CustomerId,
-		Date:           date,
+		CheckIn:        checkIn,
+		CheckOut:       checkOut,
 		NumberOfPeople: requestData.NumberOfPeople,
 		Price:          requestData.Price,
-		Room:           requestData.Room,
+		Currency:       requestData.Currency,
+		RoomCharge:     requestData.RoomCharge,
+		ExtraCharge:    requestData.ExtraCharge,
+		Discount:       requestData.Discount,
+		RoomId:         requestData.RoomId,
+		Room:           model.Room{Number: requestData.Room},
 		Note:           requestData.Note,
 	}
 	if requestData.HistoryId != uuid.Nil {
 		h.Id = requestData.HistoryId
 	}
+	if requestData.RatePlanId != uuid.Nil {
+		h.RatePlanId = &requestData.RatePlanId
+	}
+	if requestData.GroupId != uuid.Nil {
+		h.GroupId = &requestData.GroupId
+	}
 	return h, nil
 }
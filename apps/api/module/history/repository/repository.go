@@ -3,9 +3,17 @@ package repository
 import (
 	"github.com/S1nceU/CRMS/apps/api/domain"
 	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/S1nceU/CRMS/apps/api/module/internal/stay"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
+// streamBatchSize is the number of rows fetched per query while streaming
+const streamBatchSize = 500
+
 type HistoryRepository struct {
 	orm *gorm.DB
 }
@@ -18,54 +26,212 @@ func NewHistoryRepository(orm *gorm.DB) domain.HistoryRepository {
 
 func (u *HistoryRepository) ListHistories() ([]*model.History, error) {
 	var histories []*model.History
-	err := u.orm.Find(&histories).Error
+	err := u.orm.Preload("Room").Preload("TaxLines").Preload("Guests").Find(&histories).Error
 	return histories, err
 }
 
 func (u *HistoryRepository) ListHistoriesByCustomer(history *model.History) ([]*model.History, error) {
 	var histories []*model.History
-	err := u.orm.Where("CustomerId = ?", history.CustomerId).Find(&histories).Error
+	err := u.orm.Preload("Room").Preload("TaxLines").Preload("Guests").Where("CustomerId = ?", history.CustomerId).Find(&histories).Error
 	return histories, err
 }
 
 func (u *HistoryRepository) ListHistoriesForDate(history *model.History) ([]*model.History, error) {
 	var histories []*model.History
-	err := u.orm.Where("Date = ?", history.Date).Find(&histories).Error
+	err := u.orm.Preload("Room").Preload("TaxLines").Preload("Guests").Where("Kind = ? AND CheckIn <= ? AND CheckOut > ?", model.HistoryKindStay, history.CheckIn, history.CheckIn).Find(&histories).Error
 	return histories, err
 }
 
 func (u *HistoryRepository) ListHistoriesForDuring(history1 *model.History, history2 *model.History) ([]*model.History, error) {
 	var histories []*model.History
-	err := u.orm.Where("Date >= ? AND Date <= ?", history1.Date, history2.Date).Find(&histories).Error
+	err := u.orm.Preload("Room").Preload("TaxLines").Preload("Guests").Where("Kind = ? AND CheckIn < ? AND CheckOut > ?", model.HistoryKindStay, history2.CheckIn, history1.CheckIn).Find(&histories).Error
 	return histories, err
 }
 
 func (u *HistoryRepository) GetHistoryByHistoryId(history *model.History) (*model.History, error) {
-	err := u.orm.Where("Id = ?", history.Id).Find(&history).Error
+	err := u.orm.Preload("Room").Preload("TaxLines").Preload("Guests").Preload("Segments", bySegmentOrder).Preload("Segments.Room").Where("Id = ?", history.Id).Find(&history).Error
 	return history, err
 }
 
 func (u *HistoryRepository) CreateHistory(history *model.History) (*model.History, error) {
-	err := u.orm.Create(&history).Error
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&history).Error; err != nil {
+			return err
+		}
+		if err := stay.ReplaceStaySegments(tx, history); err != nil {
+			return err
+		}
+		if err := stay.ReplacePrimaryGuest(tx, history.Id, history.CustomerId); err != nil {
+			return err
+		}
+		return createTaxLines(tx, history)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = u.orm.Preload("Room").Preload("TaxLines").Preload("Guests").Preload("Segments", bySegmentOrder).Preload("Segments.Room").First(&history, "Id = ?", history.Id).Error
 	return history, err
 }
 
 func (u *HistoryRepository) UpdateHistory(history *model.History) (*model.History, error) {
-	err := u.orm.Model(history).Where("Id = ?", history.Id).Updates(&history).Error
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := stay.ReplaceStaySegments(tx, history); err != nil {
+			return err
+		}
+		if err := tx.Model(history).Select("*").Omit(clause.Associations).Where("Id = ?", history.Id).Updates(&history).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId = ?", history.Id).Delete(&model.HistoryTaxLine{}).Error; err != nil {
+			return err
+		}
+		if err := stay.ReplacePrimaryGuest(tx, history.Id, history.CustomerId); err != nil {
+			return err
+		}
+		return createTaxLines(tx, history)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = u.orm.Preload("Room").Preload("TaxLines").Preload("Guests").Preload("Segments", bySegmentOrder).Preload("Segments.Room").First(&history, "Id = ?", history.Id).Error
 	return history, err
 }
 
+// MoveHistoryRoom saves the segments of a stay after a room move, with the room it ends in as its room
+func (u *HistoryRepository) MoveHistoryRoom(history *model.History) (*model.History, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := stay.ReplaceStaySegments(tx, history); err != nil {
+			return err
+		}
+		return tx.Model(&model.History{}).Where("Id = ?", history.Id).Update("RoomId", history.RoomId).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetHistoryByHistoryId(&model.History{Id: history.Id})
+}
+
 func (u *HistoryRepository) DeleteHistory(history *model.History) error {
-	err := u.orm.Where("Id = ?", history.Id).Delete(&history).Error
-	return err
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("HistoryId = ?", history.Id).Delete(&model.HistoryTaxLine{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId = ?", history.Id).Delete(&model.StayGuest{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId = ?", history.Id).Delete(&model.StaySegment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId = ?", history.Id).Delete(&model.RegistrationSubmission{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("Id = ?", history.Id).Delete(&history).Error
+	})
 }
 
 func (u *HistoryRepository) DeleteHistoriesByCustomer(history *model.History) error {
-	err := u.orm.Where("CustomerId = ?", history.CustomerId).Delete(&history).Error
-	return err
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		stays := tx.Model(&model.History{}).Select("Id").Where("CustomerId = ?", history.CustomerId)
+		if err := tx.Where("HistoryId IN (?)", stays).Delete(&model.HistoryTaxLine{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?)", stays).Delete(&model.StayGuest{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?)", stays).Delete(&model.StaySegment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?)", stays).Delete(&model.RegistrationSubmission{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("CustomerId = ?", history.CustomerId).Delete(&history).Error
+	})
 }
 
 func (u *HistoryRepository) ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error) {
 	err := u.orm.Where("Id = ?", customer.Id).Find(&customer).Error
 	return customer, err
 }
+
+func (u *HistoryRepository) ConfirmRoomExistence(room *model.Room) (*model.Room, error) {
+	var err error
+	if room.Id != uuid.Nil {
+		err = u.orm.Where("Id = ?", room.Id).Find(&room).Error
+	} else {
+		err = u.orm.Where("Number = ?", room.Number).Find(&room).Error
+	}
+	return room, err
+}
+
+func (u *HistoryRepository) ConfirmGroupExistence(group *model.BookingGroup) (*model.BookingGroup, error) {
+	err := u.orm.Where("Id = ?", group.Id).Find(&group).Error
+	return group, err
+}
+
+func (u *HistoryRepository) CountPaymentsByHistory(history *model.History) (int64, error) {
+	var count int64
+	err := u.orm.Model(&model.Payment{}).Where("HistoryId = ?", history.Id).Count(&count).Error
+	return count, err
+}
+
+func (u *HistoryRepository) CountInvoicesByHistory(history *model.History) (int64, error) {
+	var count int64
+	groups := u.orm.Model(&model.History{}).Select("GroupId").Where("Id = ?", history.Id)
+	err := u.orm.Model(&model.Invoice{}).Where("HistoryId = ? OR GroupId IN (?)", history.Id, groups).Count(&count).Error
+	return count, err
+}
+
+func (u *HistoryRepository) CountPaymentsByCustomer(history *model.History) (int64, error) {
+	var count int64
+	stays := u.orm.Model(&model.History{}).Select("Id").Where("CustomerId = ?", history.CustomerId)
+	reservations := u.orm.Model(&model.Reservation{}).Select("Id").Where("CustomerId = ?", history.CustomerId)
+	err := u.orm.Model(&model.Payment{}).Where("HistoryId IN (?) OR ReservationId IN (?)", stays, reservations).Count(&count).Error
+	return count, err
+}
+
+func (u *HistoryRepository) CountInvoicesByCustomer(history *model.History) (int64, error) {
+	var count int64
+	stays := u.orm.Model(&model.History{}).Select("Id").Where("CustomerId = ?", history.CustomerId)
+	groups := u.orm.Model(&model.History{}).Select("GroupId").Where("CustomerId = ? AND GroupId IS NOT NULL", history.CustomerId)
+	err := u.orm.Model(&model.Invoice{}).Where("CustomerId = ? OR HistoryId IN (?) OR GroupId IN (?)", history.CustomerId, stays, groups).Count(&count).Error
+	return count, err
+}
+
+func (u *HistoryRepository) StreamHistories(filter *dto.HistoryFilter, start time.Time, end time.Time, fn func(history *model.History) error) error {
+	var histories []*model.History
+	query := u.orm.Preload("Room")
+	if filter.CustomerId != uuid.Nil {
+		query = query.Where("CustomerId = ?", filter.CustomerId)
+	}
+	if !start.IsZero() {
+		query = query.Where("CheckOut > ?", start)
+	}
+	if !end.IsZero() {
+		query = query.Where("CheckIn < ?", end)
+	}
+	if filter.Room != "" {
+		rooms := u.orm.Model(&model.Room{}).Select("Id").Where("Number = ?", model.NormalizeRoomNumber(filter.Room))
+		query = query.Where("RoomId IN (?) OR Id IN (?)", rooms, u.orm.Model(&model.StaySegment{}).Select("HistoryId").Where("RoomId IN (?)", rooms))
+	}
+	return query.FindInBatches(&histories, streamBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, history := range histories {
+			if err := fn(history); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func bySegmentOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("CheckIn")
+}
+
+func createTaxLines(tx *gorm.DB, history *model.History) error {
+	if len(history.TaxLines) == 0 {
+		return nil
+	}
+	for _, line := range history.TaxLines {
+		line.HistoryId = history.Id
+	}
+	return tx.Create(history.TaxLines).Error
+}
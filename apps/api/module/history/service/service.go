@@ -2,19 +2,23 @@ package service
 
 import (
 	"errors"
+	"github.com/S1nceU/CRMS/apps/api/config"
 	"github.com/S1nceU/CRMS/apps/api/domain"
 	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
 	"github.com/google/uuid"
 	"time"
 )
 
 type HistoryService struct {
-	repo domain.HistoryRepository
+	repo        domain.HistoryRepository
+	ratePlanSer domain.RatePlanService
 }
 
-func NewHistoryService(repo domain.HistoryRepository) domain.HistoryService {
+func NewHistoryService(repo domain.HistoryRepository, ratePlanSer domain.RatePlanService) domain.HistoryService {
 	return &HistoryService{
-		repo: repo,
+		repo:        repo,
+		ratePlanSer: ratePlanSer,
 	}
 }
 
@@ -61,7 +65,7 @@ func (u *HistoryService) ListHistoriesForDate(in string) ([]*model.History, erro
 		return nil, errors.New("error CRMS : Date is after today")
 	}
 	newHistory := &model.History{
-		Date: date,
+		CheckIn: date,
 	}
 	if point, err = u.repo.ListHistoriesForDate(newHistory); err != nil {
 		return nil, err
@@ -92,13 +96,11 @@ func (u *HistoryService) ListHistoriesForDuring(in1 string, in2 string) ([]*mode
 		return nil, errors.New("error CRMS : End date is after today")
 	}
 
-	date2 = date2.Add(time.Hour * 24)
-
 	newHistory1 := &model.History{
-		Date: date1,
+		CheckIn: date1,
 	}
 	newHistory2 := &model.History{
-		Date: date2,
+		CheckIn: date2.Add(time.Hour * 24),
 	}
 	if point, err = u.repo.ListHistoriesForDuring(newHistory1, newHistory2); len(point) == 0 {
 		return nil, errors.New("error CRMS : There is not any history between " + date1.Format("2006-01-02") + " to " + date2.Format("2006-01-02"))
@@ -138,11 +140,21 @@ func (u *HistoryService) CreateHistory(in *model.History) (*model.History, error
 	if err = validateHistoryInfo(in); err != nil {
 		return nil, err
 	}
+	if err = u.confirmRoom(in, uuid.Nil); err != nil {
+		return nil, err
+	}
+	if err = u.confirmGroup(in.GroupId); err != nil {
+		return nil, err
+	}
+	if err = u.priceStay(in, nil); err != nil {
+		return nil, err
+	}
 
 	if _, err = u.repo.ListHistoriesByCustomer(in); err != nil {
 		return nil, err
 	}
 	in.Id = uuid.New()
+	in.Kind = model.HistoryKindStay
 	if newHistory, err = u.repo.CreateHistory(in); err != nil {
 		return nil, err
 	}
@@ -168,10 +180,34 @@ func (u *HistoryService) UpdateHistory(in *model.History) (*model.History, error
 	if newHistory.CustomerId == uuid.Nil {
 		return nil, errors.New("error CRMS : There is no this history")
 	}
+	if newHistory.Kind != model.HistoryKindStay {
+		return nil, errors.New("error CRMS : Only stays can be modified")
+	}
+	in.Kind = model.HistoryKindStay
 	if err = validateHistoryInfo(in); err != nil {
 		return nil, err
 	}
-	if _, err = u.GetHistoryByHistoryId(in.Id); err != nil {
+	if in.NumberOfPeople < registeredGuests(newHistory, in.CustomerId) {
+		return nil, errors.New("error CRMS : Number of people is less than the registered guests")
+	}
+	if err = u.confirmRoom(in, newHistory.RoomId); err != nil {
+		return nil, err
+	}
+	if in.GroupId == nil {
+		in.GroupId = newHistory.GroupId
+	} else if err = u.confirmGroup(in.GroupId); err != nil {
+		return nil, err
+	}
+	// The rate plan is kept unless the stay moves to another room type, whose cheapest plan is then picked
+	if in.RatePlanId == nil && in.Room.Type == newHistory.Room.Type {
+		in.RatePlanId = newHistory.RatePlanId
+	}
+	// Price is returned as the total of the stay, a client sending it back must not make it the room charge
+	in.Price = 0
+	if err = u.priceStay(in, newHistory); err != nil {
+		return nil, err
+	}
+	if in.Segments, err = resizeSegments(newHistory, in); err != nil {
 		return nil, err
 	}
 	if newHistory, err = u.repo.UpdateHistory(in); err != nil {
@@ -181,14 +217,80 @@ func (u *HistoryService) UpdateHistory(in *model.History) (*model.History, error
 	return newHistory, err
 }
 
+// MoveHistoryRoom moves the guests of a stay to another room from date until check-out. The nights before date keep
+// their room, so the stay is split into one segment per room; its charges are left as they were.
+func (u *HistoryService) MoveHistoryRoom(in uuid.UUID, room *model.Room, date string) (*model.History, error) {
+	var err error
+	var history *model.History
+	var moveDate time.Time
+	if history, err = u.GetHistoryByHistoryId(in); err != nil {
+		return nil, err
+	}
+	if history.Kind != model.HistoryKindStay {
+		return nil, errors.New("error CRMS : Only stays can be modified")
+	}
+	if moveDate, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+		return nil, errors.New("error CRMS : Date is incomplete")
+	}
+	if !moveDate.After(history.CheckIn) || !moveDate.Before(history.CheckOut) {
+		return nil, errors.New("error CRMS : Move date must be after check-in and before check-out")
+	}
+	moved := &model.History{
+		RoomId:         room.Id,
+		Room:           *room,
+		NumberOfPeople: history.NumberOfPeople,
+	}
+	if err = u.confirmRoom(moved, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	segments := history.Segments
+	if len(segments) == 0 {
+		segments = []*model.StaySegment{{RoomId: history.RoomId, CheckIn: history.CheckIn, CheckOut: history.CheckOut}}
+	}
+	var kept []*model.StaySegment
+	for _, segment := range segments {
+		if !segment.CheckIn.After(moveDate) && segment.CheckOut.After(moveDate) && segment.RoomId == moved.RoomId {
+			return nil, errors.New("error CRMS : The guests are already in this room on the move date")
+		}
+		if !segment.CheckIn.Before(moveDate) {
+			continue
+		}
+		end := segment.CheckOut
+		if end.After(moveDate) {
+			end = moveDate
+		}
+		kept = append(kept, &model.StaySegment{RoomId: segment.RoomId, CheckIn: segment.CheckIn, CheckOut: end})
+	}
+	if last := kept[len(kept)-1]; last.RoomId == moved.RoomId {
+		last.CheckOut = history.CheckOut
+	} else {
+		kept = append(kept, &model.StaySegment{RoomId: moved.RoomId, CheckIn: moveDate, CheckOut: history.CheckOut})
+	}
+	history.Segments = kept
+	history.RoomId = moved.RoomId
+	return u.repo.MoveHistoryRoom(history)
+}
+
 func (u *HistoryService) DeleteHistory(in uuid.UUID) error {
 	var err error
 	newHistory := &model.History{
 		Id: in,
 	}
+	var count int64
 	if _, err = u.GetHistoryByHistoryId(newHistory.Id); err != nil {
 		return err
 	}
+	if count, err = u.repo.CountPaymentsByHistory(newHistory); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This history has payments, refund them instead")
+	}
+	if count, err = u.repo.CountInvoicesByHistory(newHistory); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This history has invoices and cannot be deleted")
+	}
 	if err = u.repo.DeleteHistory(newHistory); err != nil {
 		return err
 	}
@@ -200,15 +302,177 @@ func (u *HistoryService) DeleteHistoriesByCustomer(in uuid.UUID) error {
 	newHistory := &model.History{
 		CustomerId: in,
 	}
+	var count int64
 	if _, err = u.ListHistoriesByCustomerId(newHistory.CustomerId); err != nil {
 		return err
 	}
+	if count, err = u.repo.CountPaymentsByCustomer(newHistory); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This customer has payments and cannot be deleted")
+	}
+	if count, err = u.repo.CountInvoicesByCustomer(newHistory); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This customer has invoices and cannot be deleted")
+	}
 	if err = u.repo.DeleteHistoriesByCustomer(newHistory); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (u *HistoryService) StreamHistories(filter *dto.HistoryFilter, fn func(history *model.History) error) error {
+	var err error
+	var start time.Time
+	var end time.Time
+
+	if filter.StartDate != "" {
+		if start, err = time.ParseInLocation("2006-01-02", filter.StartDate, time.Local); err != nil {
+			return errors.New("error CRMS : Date is incomplete")
+		}
+	}
+	if filter.EndDate != "" {
+		if end, err = time.ParseInLocation("2006-01-02", filter.EndDate, time.Local); err != nil {
+			return errors.New("error CRMS : Date is incomplete")
+		}
+		end = end.Add(time.Hour * 24)
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return errors.New("error CRMS : Start date is after end date")
+	}
+	return u.repo.StreamHistories(filter, start, end, fn)
+}
+
+// confirmRoom resolves the room of a stay by RoomId or room number and checks the party fits in it.
+// Only a room other than currentRoomId has to be active, so past stays in a retired room can still be corrected.
+func (u *HistoryService) confirmRoom(in *model.History, currentRoomId uuid.UUID) error {
+	var err error
+	room := &model.Room{
+		Id:     in.RoomId,
+		Number: model.NormalizeRoomNumber(in.Room.Number),
+	}
+	if room.Id == uuid.Nil && room.Number == "" {
+		return errors.New("error CRMS : HistoryService Info is incomplete")
+	}
+	if room, err = u.repo.ConfirmRoomExistence(room); err != nil {
+		return err
+	} else if room.Id == uuid.Nil {
+		return errors.New("error CRMS : There is no this room")
+	}
+	if room.Id != currentRoomId && !room.Active {
+		return errors.New("error CRMS : This room is not active")
+	}
+	if in.NumberOfPeople > room.Capacity {
+		return errors.New("error CRMS : Number of people exceeds room capacity")
+	}
+	in.RoomId = room.Id
+	in.Room = *room
+	return nil
+}
+
+// confirmGroup checks the booking group the stay belongs to, nil meaning none.
+func (u *HistoryService) confirmGroup(groupId *uuid.UUID) error {
+	var err error
+	if groupId == nil {
+		return nil
+	}
+	group := &model.BookingGroup{
+		Id: *groupId,
+	}
+	if group, err = u.repo.ConfirmGroupExistence(group); err != nil {
+		return err
+	} else if group.Name == "" {
+		return errors.New("error CRMS : There is no this booking group")
+	}
+	return nil
+}
+
+// priceStay sets the room charge of the stay and computes its totals. A RoomCharge of 0 takes the legacy Price,
+// which only creations carry, or else the price quoted by the rate plan; any other RoomCharge is kept and flagged as
+// overridden when it differs from the quote, or when no rate plan was chosen. On update, current is the stored stay:
+// a RoomCharge sent back unchanged is quoted again when it was quoted, and stays overridden when it was typed by hand.
+func (u *HistoryService) priceStay(in *model.History, current *model.History) error {
+	var err error
+	var quote *dto.StayQuote
+	var planId uuid.UUID
+	var currentPlanId uuid.UUID
+	if in.Currency == "" {
+		in.Currency = config.Currency()
+	} else if in.Currency, err = model.NormalizeCurrency(in.Currency); err != nil {
+		return err
+	}
+	if in.RoomCharge == 0 && in.Price != 0 {
+		in.RoomCharge = model.MoneyFromMajor(in.Price, in.Currency).Amount
+	}
+	unchanged := current != nil && in.RoomCharge == current.RoomCharge && in.Currency == current.Currency
+	if unchanged && !current.PriceOverridden {
+		in.RoomCharge = 0
+	}
+	if in.RatePlanId != nil {
+		planId = *in.RatePlanId
+	}
+	if current != nil && current.RatePlanId != nil {
+		currentPlanId = *current.RatePlanId
+	}
+
+	if in.RoomCharge != 0 && planId == uuid.Nil {
+		in.RatePlanId = nil
+		in.PriceOverridden = true
+		return in.ComputeTotals(config.TaxRates())
+	}
+	if in.RoomCharge == 0 && in.Currency != config.Currency() {
+		return errors.New("error CRMS : Rate plans are priced in " + config.Currency() + ", enter the room charge")
+	}
+	if quote, err = u.ratePlanSer.QuoteStay(planId, currentPlanId, &in.Room, in.CheckIn, in.CheckOut, in.NumberOfPeople); err != nil {
+		return err
+	}
+	quoted := model.MoneyFromMajor(quote.Total, config.Currency())
+	in.RatePlanId = &quote.RatePlan.Id
+	if in.RoomCharge == 0 {
+		in.RoomCharge = quoted.Amount
+		in.PriceOverridden = false
+	} else {
+		in.PriceOverridden = unchanged || in.Currency != quoted.Currency || in.RoomCharge != quoted.Amount
+	}
+	return in.ComputeTotals(config.TaxRates())
+}
+
+// registeredGuests counts the guests of the stay once customerId becomes its primary guest, which drops the
+// current primary guest
+func registeredGuests(history *model.History, customerId uuid.UUID) int {
+	count := 1
+	for _, guest := range history.Guests {
+		if !guest.IsPrimary && guest.CustomerId != customerId {
+			count++
+		}
+	}
+	return count
+}
+
+// resizeSegments fits the segments of a stay that moved rooms to its new dates: the first room takes the new
+// check-in and the last one the new check-out. A stay in a single room gets its segment from the stay itself.
+func resizeSegments(current *model.History, in *model.History) ([]*model.StaySegment, error) {
+	if len(current.Segments) <= 1 {
+		return nil, nil
+	}
+	if in.RoomId != current.RoomId {
+		return nil, errors.New("error CRMS : This stay moved rooms, use a room move to change its room")
+	}
+	var segments []*model.StaySegment
+	for _, segment := range current.Segments {
+		segments = append(segments, &model.StaySegment{RoomId: segment.RoomId, CheckIn: segment.CheckIn, CheckOut: segment.CheckOut})
+	}
+	segments[0].CheckIn = in.CheckIn
+	segments[len(segments)-1].CheckOut = in.CheckOut
+	for _, segment := range segments {
+		if !segment.CheckIn.Before(segment.CheckOut) {
+			return nil, errors.New("error CRMS : The new dates leave no night in a room of this stay")
+		}
+	}
+	return segments, nil
+}
+
 func convertToSliceOfHistory(histories []*model.History) []*model.History {
 	var historiesSlice []*model.History
 	for _, history := range histories {
@@ -221,13 +485,16 @@ func validateHistoryInfo(history *model.History) error {
 	if history.CustomerId == uuid.Nil {
 		return errors.New("error CRMS : HistoryService Info is incomplete")
 	}
-	if history.Date.IsZero() {
+	if history.CheckIn.IsZero() || history.CheckOut.IsZero() {
 		return errors.New("error CRMS : HistoryService Info is incomplete")
 	}
+	if history.Nights() <= 0 {
+		return errors.New("error CRMS : Check-out date must be after check-in date")
+	}
 	if history.NumberOfPeople == 0 {
 		return errors.New("error CRMS : HistoryService Info is incomplete")
 	}
-	if history.Price == 0 {
+	if history.Price < 0 {
 		return errors.New("error CRMS : HistoryService Info is incomplete")
 	}
 	return nil
@@ -0,0 +1,115 @@
+// Package stay holds the steps shared by the repositories that book rooms and record stays. They take the
+// transaction of the caller, so a stay, its segments and its guests are saved together.
+package stay
+
+import (
+	"fmt"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// CheckRoomAvailability locks the room row so concurrent bookings of the same room are serialized, then rejects
+// the nights if another stay, an open reservation or an outage of the room overlaps them. It must run inside a
+// transaction; excludeIds are the stay and reservation being changed.
+func CheckRoomAvailability(tx *gorm.DB, roomId uuid.UUID, checkIn time.Time, checkOut time.Time, excludeIds ...uuid.UUID) error {
+	var segments []*model.StaySegment
+	var reservations []*model.Reservation
+	var outages []*model.RoomOutage
+	if len(excludeIds) == 0 {
+		excludeIds = []uuid.UUID{uuid.Nil}
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("Id = ?", roomId).Find(&model.Room{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("RoomId = ? AND HistoryId NOT IN ? AND CheckIn < ? AND CheckOut > ?", roomId, excludeIds, checkOut, checkIn).
+		Order("CheckIn").Limit(1).Find(&segments).Error; err != nil {
+		return err
+	}
+	if len(segments) != 0 {
+		return fmt.Errorf("error CRMS : Room is already booked by history %s from %s to %s",
+			segments[0].HistoryId, segments[0].CheckIn.Format("2006-01-02"), segments[0].CheckOut.Format("2006-01-02"))
+	}
+
+	if err := tx.Where("RoomId = ? AND Id NOT IN ? AND Status IN ? AND CheckIn < ? AND CheckOut > ?", roomId, excludeIds,
+		[]string{model.ReservationTentative, model.ReservationConfirmed}, checkOut, checkIn).
+		Order("CheckIn").Limit(1).Find(&reservations).Error; err != nil {
+		return err
+	}
+	if len(reservations) != 0 {
+		return fmt.Errorf("error CRMS : Room is already booked by reservation %s from %s to %s",
+			reservations[0].Id, reservations[0].CheckIn.Format("2006-01-02"), reservations[0].CheckOut.Format("2006-01-02"))
+	}
+
+	if err := tx.Where("RoomId = ? AND StartDate < ? AND EndDate >= ?", roomId, checkOut, checkIn).
+		Order("StartDate").Limit(1).Find(&outages).Error; err != nil {
+		return err
+	}
+	if len(outages) != 0 {
+		return fmt.Errorf("error CRMS : Room is already booked by room outage %s from %s to %s",
+			outages[0].Id, outages[0].StartDate.Format("2006-01-02"), outages[0].EndDate.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// ReplaceStaySegments checks every room of the stay is free for its nights and saves the segments of the stay in
+// place of the old ones. A stay given no segments spends all its nights in its room. It must run inside a
+// transaction; excludeIds are other stays or reservations being changed along with the stay.
+func ReplaceStaySegments(tx *gorm.DB, history *model.History, excludeIds ...uuid.UUID) error {
+	if len(history.Segments) == 0 {
+		history.Segments = []*model.StaySegment{{
+			RoomId:   history.RoomId,
+			CheckIn:  history.CheckIn,
+			CheckOut: history.CheckOut,
+		}}
+	}
+	excludeIds = append(excludeIds, history.Id)
+	for _, segment := range history.Segments {
+		if err := CheckRoomAvailability(tx, segment.RoomId, segment.CheckIn, segment.CheckOut, excludeIds...); err != nil {
+			return err
+		}
+		segment.Id = uuid.New()
+		segment.HistoryId = history.Id
+	}
+	if err := tx.Where("HistoryId = ?", history.Id).Delete(&model.StaySegment{}).Error; err != nil {
+		return err
+	}
+	return tx.Omit(clause.Associations).Create(history.Segments).Error
+}
+
+// TruncateStaySegments ends the stay on checkOut, dropping the segments that start on or after it. The stay keeps
+// the room of its last remaining segment, in case a planned move no longer happens.
+func TruncateStaySegments(tx *gorm.DB, historyId uuid.UUID, checkOut time.Time) error {
+	var last model.StaySegment
+	if err := tx.Where("HistoryId = ? AND CheckIn >= ?", historyId, checkOut).Delete(&model.StaySegment{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&model.StaySegment{}).Where("HistoryId = ? AND CheckOut > ?", historyId, checkOut).Update("CheckOut", checkOut).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("HistoryId = ?", historyId).Order("CheckIn DESC").Limit(1).Find(&last).Error; err != nil {
+		return err
+	}
+	if last.RoomId == uuid.Nil {
+		return nil
+	}
+	return tx.Model(&model.History{}).Where("Id = ?", historyId).Update("RoomId", last.RoomId).Error
+}
+
+// ReplacePrimaryGuest registers customerId as the primary guest of the stay. Unlike SetPrimaryGuest the previous
+// primary guest is dropped from the stay, since it was recorded under the wrong customer.
+func ReplacePrimaryGuest(tx *gorm.DB, historyId uuid.UUID, customerId uuid.UUID) error {
+	if err := tx.Where("HistoryId = ? AND IsPrimary = ? AND CustomerId <> ?", historyId, true, customerId).Delete(&model.StayGuest{}).Error; err != nil {
+		return err
+	}
+	guest := &model.StayGuest{
+		Id:         uuid.New(),
+		HistoryId:  historyId,
+		CustomerId: customerId,
+		IsPrimary:  true,
+	}
+	return tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"IsPrimary"})}).Create(guest).Error
+}
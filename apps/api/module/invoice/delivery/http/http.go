@@ -0,0 +1,352 @@
+package http
+
+import (
+	"bytes"
+	"github.com/S1nceU/CRMS/apps/api/config"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/S1nceU/CRMS/apps/api/pdf"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type InvoiceHandler struct {
+	ser domain.InvoiceService
+}
+
+func NewInvoiceHandler(e *gin.Engine, ser domain.InvoiceService) {
+	handler := &InvoiceHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/invoiceList", handler.ListInvoices)
+		api.POST("/invoiceId", handler.GetInvoiceById)
+		api.POST("/invoiceHistoryId", handler.ListInvoicesByHistoryId)
+		api.POST("/invoiceGroupId", handler.ListInvoicesByGroupId)
+		api.POST("/invoiceCre", handler.IssueInvoice)
+		api.POST("/invoiceGroupCre", handler.IssueGroupInvoice)
+		api.POST("/invoiceVoid", handler.VoidInvoice)
+		api.POST("/invoicePdf", handler.DownloadInvoicePdf)
+	}
+}
+
+// ListInvoices @Summary ListInvoices
+// @Description Get all Invoices and credit notes, newest first
+// @Tags Invoice
+// @Produce application/json
+// @Success 200 {object} []model.Invoice
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /invoiceList [post]
+func (u *InvoiceHandler) ListInvoices(c *gin.Context) {
+	invoices, err := u.ser.ListInvoices()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":  "List all invoices",
+		"invoices": invoices,
+	})
+}
+
+// GetInvoiceById @Summary GetInvoiceById
+// @Description Get Invoice by InvoiceId
+// @Tags Invoice
+// @Produce application/json
+// @Param InvoiceId body dto.InvoiceIdRequest true "Invoice id"
+// @Success 200 {object} model.Invoice
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /invoiceId [post]
+func (u *InvoiceHandler) GetInvoiceById(c *gin.Context) {
+	request := dto.InvoiceIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	invoice, err := u.ser.GetInvoiceById(request.InvoiceId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this invoice" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// ListInvoicesByHistoryId @Summary ListInvoicesByHistoryId
+// @Description Get the Invoices and credit notes of a stay
+// @Tags Invoice
+// @Produce application/json
+// @Param HistoryId body dto.InvoiceHistoryIdRequest true "History id"
+// @Success 200 {object} []model.Invoice
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /invoiceHistoryId [post]
+func (u *InvoiceHandler) ListInvoicesByHistoryId(c *gin.Context) {
+	request := dto.InvoiceHistoryIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	invoices, err := u.ser.ListInvoicesByHistoryId(request.HistoryId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":  "List invoices of the stay",
+		"invoices": invoices,
+	})
+}
+
+// ListInvoicesByGroupId @Summary ListInvoicesByGroupId
+// @Description Get the Invoices and credit notes of a booking group
+// @Tags Invoice
+// @Produce application/json
+// @Param GroupId body dto.InvoiceGroupIdRequest true "Group id"
+// @Success 200 {object} []model.Invoice
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /invoiceGroupId [post]
+func (u *InvoiceHandler) ListInvoicesByGroupId(c *gin.Context) {
+	request := dto.InvoiceGroupIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	invoices, err := u.ser.ListInvoicesByGroupId(request.GroupId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this booking group" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":  "List invoices of the group",
+		"invoices": invoices,
+	})
+}
+
+// IssueInvoice @Summary IssueInvoice
+// @Description Issue a numbered Invoice from the price breakdown and payments of a stay
+// @Tags Invoice
+// @Accept json
+// @Produce application/json
+// @Param Invoice body dto.InvoiceRequest true "History id and note" example: {"HistoryId": "00000000-0000-0000-0000-000000000000", "Note": ""}
+// @Success 200 {object} model.Invoice
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /invoiceCre [post]
+func (u *InvoiceHandler) IssueInvoice(c *gin.Context) {
+	request := dto.InvoiceRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	invoice, err := u.ser.IssueInvoice(request.HistoryId, request.Note)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this customer" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This stay already has an invoice, void it first" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This stay is invoiced with its group" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// IssueGroupInvoice @Summary IssueGroupInvoice
+// @Description Issue one numbered Invoice to the organizer of a booking group, covering every stay and fee of the group
+// @Tags Invoice
+// @Accept json
+// @Produce application/json
+// @Param Invoice body dto.InvoiceGroupRequest true "Group id and note" example: {"GroupId": "00000000-0000-0000-0000-000000000000", "Note": ""}
+// @Success 200 {object} model.Invoice
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /invoiceGroupCre [post]
+func (u *InvoiceHandler) IssueGroupInvoice(c *gin.Context) {
+	request := dto.InvoiceGroupRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	invoice, err := u.ser.IssueGroupInvoice(request.GroupId, request.Note)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this booking group" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This group already has an invoice, void it first" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This group has no stays to invoice" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Stays of this group are in different currencies" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : A stay of this group already has an invoice, void it first" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// VoidInvoice @Summary VoidInvoice
+// @Description Void an Invoice and return the credit note issued for it
+// @Tags Invoice
+// @Accept json
+// @Produce application/json
+// @Param Invoice body dto.InvoiceVoidRequest true "Invoice id and reason" example: {"InvoiceId": "00000000-0000-0000-0000-000000000000", "Reason": "Wrong guest name"}
+// @Success 200 {object} model.Invoice
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /invoiceVoid [post]
+func (u *InvoiceHandler) VoidInvoice(c *gin.Context) {
+	request := dto.InvoiceVoidRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	creditNote, err := u.ser.VoidInvoice(request.InvoiceId, request.Reason)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this invoice" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Void reason is required" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Only invoices can be voided" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This invoice is already voided" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, creditNote)
+}
+
+// DownloadInvoicePdf @Summary DownloadInvoicePdf
+// @Description Download an Invoice or credit note as PDF
+// @Tags Invoice
+// @Accept json
+// @Produce application/pdf
+// @Param InvoiceId body dto.InvoiceIdRequest true "Invoice id"
+// @Success 200 {file} file
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /invoicePdf [post]
+func (u *InvoiceHandler) DownloadInvoicePdf(c *gin.Context) {
+	request := dto.InvoiceIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	invoice, err := u.ser.GetInvoiceById(request.InvoiceId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this invoice" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	buffer := &bytes.Buffer{}
+	if err = pdf.WriteInvoice(buffer, config.Val.PropertyConfig, invoice); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+invoice.Number+".pdf")
+	c.Data(http.StatusOK, "application/pdf", buffer.Bytes())
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvoiceRepository struct {
+	orm *gorm.DB
+}
+
+func NewInvoiceRepository(orm *gorm.DB) domain.InvoiceRepository {
+	return &InvoiceRepository{
+		orm: orm,
+	}
+}
+
+func (u *InvoiceRepository) ListInvoices() ([]*model.Invoice, error) {
+	var invoices []*model.Invoice
+	err := u.orm.Preload("Lines", orderLines).Order("IssuedAt DESC").Find(&invoices).Error
+	return invoices, err
+}
+
+func (u *InvoiceRepository) ListInvoicesByHistory(invoice *model.Invoice) ([]*model.Invoice, error) {
+	var invoices []*model.Invoice
+	err := u.orm.Preload("Lines", orderLines).Where("HistoryId = ?", invoice.HistoryId).Order("IssuedAt").Find(&invoices).Error
+	return invoices, err
+}
+
+func (u *InvoiceRepository) ListInvoicesByGroup(invoice *model.Invoice) ([]*model.Invoice, error) {
+	var invoices []*model.Invoice
+	err := u.orm.Preload("Lines", orderLines).Where("GroupId = ?", invoice.GroupId).Order("IssuedAt").Find(&invoices).Error
+	return invoices, err
+}
+
+func (u *InvoiceRepository) GetInvoiceById(invoice *model.Invoice) (*model.Invoice, error) {
+	err := u.orm.Preload("Lines", orderLines).Where("Id = ?", invoice.Id).Find(&invoice).Error
+	return invoice, err
+}
+
+// CreateInvoice numbers and saves invoice once no invoice in force covers its stays
+func (u *InvoiceRepository) CreateInvoice(invoice *model.Invoice, series string) (*model.Invoice, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if err = confirmNotInvoiced(tx, invoice); err != nil {
+			return err
+		}
+		if invoice.Number, err = nextNumber(tx, series, invoice.IssuedAt.Year()); err != nil {
+			return err
+		}
+		return tx.Create(&invoice).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetInvoiceById(invoice)
+}
+
+func (u *InvoiceRepository) VoidInvoice(invoice *model.Invoice, creditNote *model.Invoice, series string) (*model.Invoice, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result := tx.Model(&model.Invoice{}).Where("Id = ? AND VoidedAt IS NULL", invoice.Id).Updates(map[string]interface{}{
+			"VoidedAt":     creditNote.IssuedAt,
+			"VoidReason":   invoice.VoidReason,
+			"CreditNoteId": creditNote.Id,
+		})
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return errors.New("error CRMS : This invoice is already voided")
+		}
+		if creditNote.Number, err = nextNumber(tx, series, creditNote.IssuedAt.Year()); err != nil {
+			return err
+		}
+		return tx.Create(&creditNote).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetInvoiceById(creditNote)
+}
+
+func (u *InvoiceRepository) GetHistoryForInvoice(history *model.History) (*model.History, error) {
+	err := u.orm.Preload("Room").Preload("TaxLines").Where("Id = ?", history.Id).Find(&history).Error
+	return history, err
+}
+
+func (u *InvoiceRepository) GetGroupForInvoice(group *model.BookingGroup) (*model.BookingGroup, error) {
+	err := u.orm.Preload("Organizer").Where("Id = ?", group.Id).Find(&group).Error
+	return group, err
+}
+
+func (u *InvoiceRepository) ListHistoriesByGroup(history *model.History) ([]*model.History, error) {
+	var histories []*model.History
+	err := u.orm.Preload("Room").Preload("TaxLines").Where("GroupId = ?", history.GroupId).Order("CheckIn").Find(&histories).Error
+	return histories, err
+}
+
+func (u *InvoiceRepository) SumPaymentsByHistory(history *model.History) (int64, error) {
+	var paid int64
+	err := u.orm.Model(&model.Payment{}).
+		Select("COALESCE(SUM(CASE WHEN Kind = ? THEN -Amount ELSE Amount END), 0)", model.PaymentKindRefund).
+		Where("HistoryId = ?", history.Id).Scan(&paid).Error
+	return paid, err
+}
+
+func (u *InvoiceRepository) ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error) {
+	err := u.orm.Where("Id = ?", customer.Id).Find(&customer).Error
+	return customer, err
+}
+
+// confirmNotInvoiced fails when a stay covered by invoice, or its booking group, already has an invoice in force.
+// The stays stay locked until the transaction ends, so concurrent invoices of the same stay are issued one after the
+// other and the later one sees the invoice of the first.
+func confirmNotInvoiced(tx *gorm.DB, invoice *model.Invoice) error {
+	var stays []*model.History
+	var count int64
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("Id", "GroupId")
+	if invoice.GroupId != nil {
+		query = query.Where("GroupId = ?", invoice.GroupId)
+	} else {
+		query = query.Where("Id = ?", invoice.HistoryId)
+	}
+	if err := query.Find(&stays).Error; err != nil {
+		return err
+	}
+	inForce := func() *gorm.DB {
+		return tx.Model(&model.Invoice{}).Where("Kind = ? AND VoidedAt IS NULL", model.InvoiceKindInvoice)
+	}
+
+	if invoice.GroupId != nil {
+		if err := inForce().Where("GroupId = ?", invoice.GroupId).Count(&count).Error; err != nil {
+			return err
+		} else if count != 0 {
+			return errors.New("error CRMS : This group already has an invoice, void it first")
+		}
+		ids := make([]uuid.UUID, 0, len(stays))
+		for _, stay := range stays {
+			ids = append(ids, stay.Id)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := inForce().Where("HistoryId IN ?", ids).Count(&count).Error; err != nil {
+			return err
+		} else if count != 0 {
+			return errors.New("error CRMS : A stay of this group already has an invoice, void it first")
+		}
+		return nil
+	}
+
+	if err := inForce().Where("HistoryId = ?", invoice.HistoryId).Count(&count).Error; err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This stay already has an invoice, void it first")
+	}
+	if len(stays) != 0 && stays[0].GroupId != nil {
+		if err := inForce().Where("GroupId = ?", stays[0].GroupId).Count(&count).Error; err != nil {
+			return err
+		} else if count != 0 {
+			return errors.New("error CRMS : This stay is invoiced with its group")
+		}
+	}
+	return nil
+}
+
+// nextNumber takes the next number of the series in the year. The sequence row stays locked until the transaction
+// ends, so concurrent invoices wait for each other and a rolled back invoice gives its number back.
+func nextNumber(tx *gorm.DB, series string, year int) (string, error) {
+	sequence := &model.InvoiceSequence{Series: series, Year: year}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(sequence).Error; err != nil {
+		return "", err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("Series = ? AND Year = ?", series, year).First(sequence).Error; err != nil {
+		return "", err
+	}
+	sequence.Last++
+	if err := tx.Model(&model.InvoiceSequence{}).Where("Series = ? AND Year = ?", series, year).Update("Last", sequence.Last).Error; err != nil {
+		return "", err
+	}
+	return model.InvoiceNumber(series, year, sequence.Last), nil
+}
+
+func orderLines(db *gorm.DB) *gorm.DB {
+	return db.Order("Position")
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+	"strconv"
+	"time"
+)
+
+const (
+	invoiceSeries    = "INV"
+	creditNoteSeries = "CN"
+)
+
+type InvoiceService struct {
+	repo domain.InvoiceRepository
+}
+
+func NewInvoiceService(repo domain.InvoiceRepository) domain.InvoiceService {
+	return &InvoiceService{
+		repo: repo,
+	}
+}
+
+func (u *InvoiceService) ListInvoices() ([]*model.Invoice, error) {
+	var err error
+	var invoices []*model.Invoice
+	if invoices, err = u.repo.ListInvoices(); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfInvoice(invoices), err
+}
+
+func (u *InvoiceService) ListInvoicesByHistoryId(historyId uuid.UUID) ([]*model.Invoice, error) {
+	var err error
+	var invoices []*model.Invoice
+	if _, err = u.confirmHistory(historyId); err != nil {
+		return nil, err
+	}
+	if invoices, err = u.repo.ListInvoicesByHistory(&model.Invoice{HistoryId: &historyId}); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfInvoice(invoices), err
+}
+
+func (u *InvoiceService) ListInvoicesByGroupId(groupId uuid.UUID) ([]*model.Invoice, error) {
+	var err error
+	var invoices []*model.Invoice
+	if invoices, err = u.repo.ListInvoicesByGroup(&model.Invoice{GroupId: &groupId}); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfInvoice(invoices), err
+}
+
+func (u *InvoiceService) GetInvoiceById(invoiceId uuid.UUID) (*model.Invoice, error) {
+	var err error
+	invoice := &model.Invoice{
+		Id: invoiceId,
+	}
+	if invoice, err = u.repo.GetInvoiceById(invoice); err != nil {
+		return nil, err
+	} else if invoice.Number == "" {
+		return nil, errors.New("error CRMS : There is no this invoice")
+	}
+	return invoice, err
+}
+
+// IssueInvoice copies the price breakdown of a stay into a new invoice. A stay has at most one invoice in force,
+// either its own or the one of its booking group.
+func (u *InvoiceService) IssueInvoice(historyId uuid.UUID, note string) (*model.Invoice, error) {
+	var err error
+	var history *model.History
+	var customer *model.Customer
+	var paid int64
+	if history, err = u.confirmHistory(historyId); err != nil {
+		return nil, err
+	}
+	if customer, err = u.repo.ConfirmCustomerExistence(&model.Customer{Id: history.CustomerId}); err != nil {
+		return nil, err
+	}
+	if paid, err = u.repo.SumPaymentsByHistory(history); err != nil {
+		return nil, err
+	}
+
+	invoice := &model.Invoice{
+		Id:           uuid.New(),
+		Kind:         model.InvoiceKindInvoice,
+		HistoryId:    &history.Id,
+		CustomerId:   history.CustomerId,
+		CustomerName: customer.Name,
+		Currency:     history.Currency,
+		TaxAmount:    history.TaxAmount,
+		TotalAmount:  history.TotalAmount,
+		Paid:         paid,
+		IssuedAt:     time.Now(),
+		Note:         note,
+	}
+	invoice.Lines = invoiceLines(invoice.Id, []*model.History{history})
+	return u.repo.CreateInvoice(invoice, invoiceSeries)
+}
+
+// IssueGroupInvoice invoices every stay of a booking group to its organizer. Stays already invoiced on their own
+// must be voided first, so no charge is invoiced twice.
+func (u *InvoiceService) IssueGroupInvoice(groupId uuid.UUID, note string) (*model.Invoice, error) {
+	var err error
+	var group *model.BookingGroup
+	var histories []*model.History
+	var paid int64
+	group = &model.BookingGroup{
+		Id: groupId,
+	}
+	if group, err = u.repo.GetGroupForInvoice(group); err != nil {
+		return nil, err
+	} else if group.Name == "" {
+		return nil, errors.New("error CRMS : There is no this booking group")
+	}
+	if histories, err = u.repo.ListHistoriesByGroup(&model.History{GroupId: &group.Id}); err != nil {
+		return nil, err
+	} else if len(histories) == 0 {
+		return nil, errors.New("error CRMS : This group has no stays to invoice")
+	}
+	invoice := &model.Invoice{
+		Id:           uuid.New(),
+		Kind:         model.InvoiceKindInvoice,
+		GroupId:      &group.Id,
+		CustomerId:   group.OrganizerId,
+		CustomerName: group.Organizer.Name,
+		Currency:     histories[0].Currency,
+		IssuedAt:     time.Now(),
+		Note:         note,
+	}
+	for _, history := range histories {
+		if history.Currency != invoice.Currency {
+			return nil, errors.New("error CRMS : Stays of this group are in different currencies")
+		}
+		if paid, err = u.repo.SumPaymentsByHistory(history); err != nil {
+			return nil, err
+		}
+		invoice.TaxAmount += history.TaxAmount
+		invoice.TotalAmount += history.TotalAmount
+		invoice.Paid += paid
+	}
+	invoice.Lines = invoiceLines(invoice.Id, histories)
+	return u.repo.CreateInvoice(invoice, invoiceSeries)
+}
+
+// VoidInvoice cancels an invoice with a credit note of the opposite amounts, which is returned
+func (u *InvoiceService) VoidInvoice(invoiceId uuid.UUID, reason string) (*model.Invoice, error) {
+	var err error
+	var invoice *model.Invoice
+	if reason == "" {
+		return nil, errors.New("error CRMS : Void reason is required")
+	}
+	if invoice, err = u.GetInvoiceById(invoiceId); err != nil {
+		return nil, err
+	}
+	if invoice.Kind != model.InvoiceKindInvoice {
+		return nil, errors.New("error CRMS : Only invoices can be voided")
+	}
+	if invoice.VoidedAt != nil {
+		return nil, errors.New("error CRMS : This invoice is already voided")
+	}
+
+	creditNote := &model.Invoice{
+		Id:                uuid.New(),
+		Kind:              model.InvoiceKindCreditNote,
+		HistoryId:         invoice.HistoryId,
+		GroupId:           invoice.GroupId,
+		CustomerId:        invoice.CustomerId,
+		CustomerName:      invoice.CustomerName,
+		Currency:          invoice.Currency,
+		TaxAmount:         -invoice.TaxAmount,
+		TotalAmount:       -invoice.TotalAmount,
+		IssuedAt:          time.Now(),
+		OriginalInvoiceId: &invoice.Id,
+		Note:              "Voids " + invoice.Number + ": " + reason,
+	}
+	for _, line := range invoice.Lines {
+		creditNote.Lines = append(creditNote.Lines, &model.InvoiceLine{
+			Id:          uuid.New(),
+			InvoiceId:   creditNote.Id,
+			Position:    line.Position,
+			Description: line.Description,
+			Amount:      -line.Amount,
+			Included:    line.Included,
+		})
+	}
+	invoice.VoidReason = reason
+	return u.repo.VoidInvoice(invoice, creditNote, creditNoteSeries)
+}
+
+func (u *InvoiceService) confirmHistory(historyId uuid.UUID) (*model.History, error) {
+	var err error
+	history := &model.History{
+		Id: historyId,
+	}
+	if history, err = u.repo.GetHistoryForInvoice(history); err != nil {
+		return nil, err
+	} else if history.CustomerId == uuid.Nil {
+		return nil, errors.New("error CRMS : There is no this history")
+	}
+	return history, nil
+}
+
+// invoiceLines lists the room charge, extras, discount and fees of every stay in the order they are printed,
+// followed by the taxes of all the stays added up per rate
+func invoiceLines(invoiceId uuid.UUID, histories []*model.History) []*model.InvoiceLine {
+	var lines []*model.InvoiceLine
+	add := func(description string, amount int64, included bool) {
+		lines = append(lines, &model.InvoiceLine{
+			Id:          uuid.New(),
+			InvoiceId:   invoiceId,
+			Position:    len(lines) + 1,
+			Description: description,
+			Amount:      amount,
+			Included:    included,
+		})
+	}
+
+	var taxes []*model.HistoryTaxLine
+	for _, history := range histories {
+		dates := " (" + history.CheckIn.Format("2006-01-02") + " - " + history.CheckOut.Format("2006-01-02") + ")"
+		switch history.Kind {
+		case model.HistoryKindCancellationFee:
+			add("Cancellation fee, Room "+history.Room.Number+dates, history.ExtraCharge, false)
+		case model.HistoryKindNoShowFee:
+			add("No-show fee, Room "+history.Room.Number+dates, history.ExtraCharge, false)
+		default:
+			nights := history.Nights()
+			room := fmt.Sprintf("Room %s, %d night", history.Room.Number, nights)
+			if nights != 1 {
+				room += "s"
+			}
+			add(room+dates, history.RoomCharge, false)
+			if history.ExtraCharge != 0 {
+				add("Extra charges", history.ExtraCharge, false)
+			}
+		}
+		if history.Discount != 0 {
+			add("Discount", -history.Discount, false)
+		}
+		for _, line := range history.TaxLines {
+			taxes = addTax(taxes, line)
+		}
+	}
+	for _, tax := range taxes {
+		description := tax.Name + " " + strconv.FormatFloat(tax.Rate, 'f', -1, 64) + "%"
+		if tax.Inclusive {
+			description += " included"
+		}
+		add(description, tax.Amount, tax.Inclusive)
+	}
+	return lines
+}
+
+// addTax adds line to the total of its tax in taxes, keeping the order in which taxes first appear
+func addTax(taxes []*model.HistoryTaxLine, line *model.HistoryTaxLine) []*model.HistoryTaxLine {
+	for _, tax := range taxes {
+		if tax.Name == line.Name && tax.Rate == line.Rate && tax.Inclusive == line.Inclusive {
+			tax.Amount += line.Amount
+			return taxes
+		}
+	}
+	return append(taxes, &model.HistoryTaxLine{
+		Name:      line.Name,
+		Rate:      line.Rate,
+		Inclusive: line.Inclusive,
+		Amount:    line.Amount,
+	})
+}
+
+func convertToSliceOfInvoice(invoices []*model.Invoice) []*model.Invoice {
+	var invoicesSlice []*model.Invoice
+	for _, invoice := range invoices {
+		invoicesSlice = append(invoicesSlice, invoice)
+	}
+	return invoicesSlice
+}
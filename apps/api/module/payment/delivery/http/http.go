@@ -0,0 +1,298 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+type PaymentHandler struct {
+	ser domain.PaymentService
+}
+
+func NewPaymentHandler(e *gin.Engine, ser domain.PaymentService) {
+	handler := &PaymentHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/paymentCre", handler.CreatePayment)
+		api.POST("/paymentHistoryId", handler.ListPaymentsByHistoryId)
+		api.POST("/paymentReservationId", handler.ListPaymentsByReservationId)
+		api.POST("/paymentStayBalance", handler.GetStayBalance)
+		api.POST("/paymentCustomerBalance", handler.GetCustomerBalance)
+		api.POST("/paymentUnpaidList", handler.ListUnpaidStays)
+	}
+}
+
+// CreatePayment @Summary CreatePayment
+// @Description Record a payment or a refund of a stay, or a deposit of a reservation
+// @Tags Payment
+// @Accept json
+// @Produce application/json
+// @Param Payment body dto.PaymentRequest true "Payment Information" example: {"HistoryId": "00000000-0000-0000-0000-000000000000", "Kind": "payment", "Method": "cash", "Amount": 240000, "ReceivedBy": "admin"}
+// @Success 200 {object} model.Payment
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /paymentCre [post]
+func (u *PaymentHandler) CreatePayment(c *gin.Context) {
+	request := dto.PaymentRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	payment, err := transformToPayment(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	payment, err = u.ser.CreatePayment(payment)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this reservation" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Payment Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Payment kind is invalid" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Payment method is invalid" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Currency is invalid" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Payment currency does not match the stay" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Deposits can only be taken for open reservations" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Refund exceeds the amount paid" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, payment)
+}
+
+// ListPaymentsByHistoryId @Summary ListPaymentsByHistoryId
+// @Description Get the Payments of a stay, deposits of its reservation included
+// @Tags Payment
+// @Produce application/json
+// @Param HistoryId body dto.PaymentHistoryIdRequest true "History id"
+// @Success 200 {object} []model.Payment
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /paymentHistoryId [post]
+func (u *PaymentHandler) ListPaymentsByHistoryId(c *gin.Context) {
+	request := dto.PaymentHistoryIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	payments, err := u.ser.ListPaymentsByHistoryId(request.HistoryId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":  "List payments of the stay",
+		"payments": payments,
+	})
+}
+
+// ListPaymentsByReservationId @Summary ListPaymentsByReservationId
+// @Description Get the deposits of a Reservation
+// @Tags Payment
+// @Produce application/json
+// @Param ReservationId body dto.PaymentReservationIdRequest true "Reservation id"
+// @Success 200 {object} []model.Payment
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /paymentReservationId [post]
+func (u *PaymentHandler) ListPaymentsByReservationId(c *gin.Context) {
+	request := dto.PaymentReservationIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	payments, err := u.ser.ListPaymentsByReservationId(request.ReservationId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this reservation" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":  "List payments of the reservation",
+		"payments": payments,
+	})
+}
+
+// GetStayBalance @Summary GetStayBalance
+// @Description Get the charged, paid and outstanding amounts of a stay
+// @Tags Payment
+// @Produce application/json
+// @Param HistoryId body dto.PaymentHistoryIdRequest true "History id"
+// @Success 200 {object} dto.StayBalance
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /paymentStayBalance [post]
+func (u *PaymentHandler) GetStayBalance(c *gin.Context) {
+	request := dto.PaymentHistoryIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	balance, err := u.ser.GetStayBalance(request.HistoryId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetCustomerBalance @Summary GetCustomerBalance
+// @Description Get the outstanding balance of a Customer per currency, with the balance of each stay
+// @Tags Payment
+// @Produce application/json
+// @Param CustomerId body dto.PaymentCustomerIdRequest true "Customer id"
+// @Success 200 {object} dto.CustomerBalance
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /paymentCustomerBalance [post]
+func (u *PaymentHandler) GetCustomerBalance(c *gin.Context) {
+	request := dto.PaymentCustomerIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	balance, err := u.ser.GetCustomerBalance(request.CustomerId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this customer" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, balance)
+}
+
+// ListUnpaidStays @Summary ListUnpaidStays
+// @Description Get the stays that are unpaid or partially paid
+// @Tags Payment
+// @Produce application/json
+// @Success 200 {object} []dto.StayBalance
+// @Failure 500 {string} string "{"Message": "Internal Error!"}"
+// @Router /paymentUnpaidList [post]
+func (u *PaymentHandler) ListUnpaidStays(c *gin.Context) {
+	balances, err := u.ser.ListUnpaidStays()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": "Internal Error!",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List unpaid stays",
+		"stays":   balances,
+	})
+}
+
+func transformToPayment(requestData dto.PaymentRequest) (*model.Payment, error) {
+	payment := &model.Payment{
+		Kind:       requestData.Kind,
+		Method:     requestData.Method,
+		Amount:     requestData.Amount,
+		Currency:   requestData.Currency,
+		Reference:  requestData.Reference,
+		ReceivedBy: requestData.ReceivedBy,
+		Note:       requestData.Note,
+	}
+	if requestData.HistoryId != uuid.Nil {
+		payment.HistoryId = &requestData.HistoryId
+	}
+	if requestData.ReservationId != uuid.Nil {
+		payment.ReservationId = &requestData.ReservationId
+	}
+	if requestData.PaidAt != "" {
+		paidAt, err := time.ParseInLocation("2006-01-02 15:04", requestData.PaidAt, time.Local)
+		if err != nil {
+			if paidAt, err = time.ParseInLocation("2006-01-02", requestData.PaidAt, time.Local); err != nil {
+				return nil, err
+			}
+		}
+		payment.PaidAt = paidAt
+	}
+	return payment, nil
+}
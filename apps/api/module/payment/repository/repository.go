@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository struct {
+	orm *gorm.DB
+}
+
+func NewPaymentRepository(orm *gorm.DB) domain.PaymentRepository {
+	return &PaymentRepository{
+		orm: orm,
+	}
+}
+
+func (u *PaymentRepository) ListPaymentsByHistory(payment *model.Payment) ([]*model.Payment, error) {
+	var payments []*model.Payment
+	err := u.orm.Where("HistoryId = ?", payment.HistoryId).Order("PaidAt").Find(&payments).Error
+	return payments, err
+}
+
+func (u *PaymentRepository) ListPaymentsByReservation(payment *model.Payment) ([]*model.Payment, error) {
+	var payments []*model.Payment
+	err := u.orm.Where("ReservationId = ?", payment.ReservationId).Order("PaidAt").Find(&payments).Error
+	return payments, err
+}
+
+func (u *PaymentRepository) CreatePayment(payment *model.Payment) (*model.Payment, error) {
+	err := u.orm.Create(&payment).Error
+	return payment, err
+}
+
+// ListStayBalances sums the payments and refunds of every stay matching the filter in one query
+func (u *PaymentRepository) ListStayBalances(filter *dto.StayBalanceFilter) ([]*dto.StayBalance, error) {
+	var balances []*dto.StayBalance
+	query := u.orm.Table("histories h").
+		Select(`h.Id AS HistoryId, h.CustomerId, c.Name AS CustomerName, h.CheckIn, h.CheckOut, h.Currency,
+			h.TotalAmount AS Total,
+			COALESCE(SUM(CASE WHEN p.Kind = ? THEN p.Amount ELSE 0 END), 0) AS Paid,
+			COALESCE(SUM(CASE WHEN p.Kind = ? THEN p.Amount ELSE 0 END), 0) AS Refunded`,
+			model.PaymentKindPayment, model.PaymentKindRefund).
+		Joins("JOIN customers c ON c.Id = h.CustomerId").
+		Joins("LEFT JOIN payments p ON p.HistoryId = h.Id").
+		Group("h.Id, h.CustomerId, c.Name, h.CheckIn, h.CheckOut, h.Currency, h.TotalAmount").
+		Order("h.CheckIn")
+	if filter.HistoryId != uuid.Nil {
+		query = query.Where("h.Id = ?", filter.HistoryId)
+	}
+	if filter.CustomerId != uuid.Nil {
+		query = query.Where("h.CustomerId = ?", filter.CustomerId)
+	}
+	if filter.UnpaidOnly {
+		query = query.Having("Total - Paid + Refunded > 0")
+	}
+	err := query.Scan(&balances).Error
+	return balances, err
+}
+
+func (u *PaymentRepository) ConfirmHistoryExistence(history *model.History) (*model.History, error) {
+	err := u.orm.Where("Id = ?", history.Id).Find(&history).Error
+	return history, err
+}
+
+func (u *PaymentRepository) ConfirmReservationExistence(reservation *model.Reservation) (*model.Reservation, error) {
+	err := u.orm.Where("Id = ?", reservation.Id).Find(&reservation).Error
+	return reservation, err
+}
+
+func (u *PaymentRepository) ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error) {
+	err := u.orm.Where("Id = ?", customer.Id).Find(&customer).Error
+	return customer, err
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/config"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PaymentService struct {
+	repo domain.PaymentRepository
+}
+
+func NewPaymentService(repo domain.PaymentRepository) domain.PaymentService {
+	return &PaymentService{
+		repo: repo,
+	}
+}
+
+func (u *PaymentService) ListPaymentsByHistoryId(historyId uuid.UUID) ([]*model.Payment, error) {
+	var err error
+	var payments []*model.Payment
+	if _, err = u.confirmHistory(historyId); err != nil {
+		return nil, err
+	}
+	if payments, err = u.repo.ListPaymentsByHistory(&model.Payment{HistoryId: &historyId}); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfPayment(payments), err
+}
+
+func (u *PaymentService) ListPaymentsByReservationId(reservationId uuid.UUID) ([]*model.Payment, error) {
+	var err error
+	var payments []*model.Payment
+	if _, err = u.confirmReservation(reservationId); err != nil {
+		return nil, err
+	}
+	if payments, err = u.repo.ListPaymentsByReservation(&model.Payment{ReservationId: &reservationId}); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfPayment(payments), err
+}
+
+// CreatePayment records a payment or a refund against a stay, or a deposit against an open reservation.
+// A refund can never take back more than was paid.
+func (u *PaymentService) CreatePayment(payment *model.Payment) (*model.Payment, error) {
+	var err error
+	var paid int64
+	if err = validatePaymentInfo(payment); err != nil {
+		return nil, err
+	}
+
+	if payment.HistoryId != nil && *payment.HistoryId != uuid.Nil {
+		var history *model.History
+		var balances []*dto.StayBalance
+		if history, err = u.confirmHistory(*payment.HistoryId); err != nil {
+			return nil, err
+		}
+		if err = matchCurrency(payment, history.Currency); err != nil {
+			return nil, err
+		}
+		if balances, err = u.repo.ListStayBalances(&dto.StayBalanceFilter{HistoryId: history.Id}); err != nil {
+			return nil, err
+		}
+		for _, balance := range balances {
+			paid += balance.Paid - balance.Refunded
+		}
+		payment.ReservationId = nil
+	} else if payment.ReservationId != nil && *payment.ReservationId != uuid.Nil {
+		var reservation *model.Reservation
+		var payments []*model.Payment
+		if reservation, err = u.confirmReservation(*payment.ReservationId); err != nil {
+			return nil, err
+		}
+		if payment.Kind == model.PaymentKindPayment &&
+			reservation.Status != model.ReservationTentative && reservation.Status != model.ReservationConfirmed {
+			return nil, errors.New("error CRMS : Deposits can only be taken for open reservations")
+		}
+		if err = matchCurrency(payment, config.Currency()); err != nil {
+			return nil, err
+		}
+		if payments, err = u.repo.ListPaymentsByReservation(payment); err != nil {
+			return nil, err
+		}
+		for _, deposit := range payments {
+			paid += deposit.Signed()
+		}
+		payment.HistoryId = reservation.HistoryId
+	} else {
+		return nil, errors.New("error CRMS : Payment Info is incomplete")
+	}
+
+	if payment.Kind == model.PaymentKindRefund && payment.Amount > paid {
+		return nil, errors.New("error CRMS : Refund exceeds the amount paid")
+	}
+	if payment.PaidAt.IsZero() {
+		payment.PaidAt = time.Now()
+	}
+	payment.Id = uuid.New()
+	return u.repo.CreatePayment(payment)
+}
+
+func (u *PaymentService) GetStayBalance(historyId uuid.UUID) (*dto.StayBalance, error) {
+	var err error
+	var balances []*dto.StayBalance
+	if balances, err = u.repo.ListStayBalances(&dto.StayBalanceFilter{HistoryId: historyId}); err != nil {
+		return nil, err
+	} else if len(balances) == 0 {
+		return nil, errors.New("error CRMS : There is no this history")
+	}
+	return fillBalance(balances[0]), nil
+}
+
+func (u *PaymentService) GetCustomerBalance(customerId uuid.UUID) (*dto.CustomerBalance, error) {
+	var err error
+	var balances []*dto.StayBalance
+	customer := &model.Customer{
+		Id: customerId,
+	}
+	if customer, err = u.repo.ConfirmCustomerExistence(customer); err != nil {
+		return nil, err
+	} else if customer.Name == "" {
+		return nil, errors.New("error CRMS : There is no this customer")
+	}
+	if balances, err = u.repo.ListStayBalances(&dto.StayBalanceFilter{CustomerId: customerId}); err != nil {
+		return nil, err
+	}
+
+	result := &dto.CustomerBalance{
+		CustomerId: customerId,
+		Balances:   []model.Money{},
+		Stays:      make([]*dto.StayBalance, 0, len(balances)),
+	}
+	totals := make(map[string]int, len(balances))
+	for _, balance := range balances {
+		fillBalance(balance)
+		result.Stays = append(result.Stays, balance)
+		if i, ok := totals[balance.Currency]; ok {
+			result.Balances[i].Amount += balance.Balance
+			continue
+		}
+		totals[balance.Currency] = len(result.Balances)
+		result.Balances = append(result.Balances, model.Money{Amount: balance.Balance, Currency: balance.Currency})
+	}
+	return result, nil
+}
+
+func (u *PaymentService) ListUnpaidStays() ([]*dto.StayBalance, error) {
+	var err error
+	var balances []*dto.StayBalance
+	if balances, err = u.repo.ListStayBalances(&dto.StayBalanceFilter{UnpaidOnly: true}); err != nil {
+		return nil, err
+	}
+	for _, balance := range balances {
+		fillBalance(balance)
+	}
+	return balances, nil
+}
+
+func (u *PaymentService) confirmHistory(historyId uuid.UUID) (*model.History, error) {
+	var err error
+	history := &model.History{
+		Id: historyId,
+	}
+	if history, err = u.repo.ConfirmHistoryExistence(history); err != nil {
+		return nil, err
+	} else if history.CustomerId == uuid.Nil {
+		return nil, errors.New("error CRMS : There is no this history")
+	}
+	return history, nil
+}
+
+func (u *PaymentService) confirmReservation(reservationId uuid.UUID) (*model.Reservation, error) {
+	var err error
+	reservation := &model.Reservation{
+		Id: reservationId,
+	}
+	if reservation, err = u.repo.ConfirmReservationExistence(reservation); err != nil {
+		return nil, err
+	} else if reservation.CustomerId == uuid.Nil {
+		return nil, errors.New("error CRMS : There is no this reservation")
+	}
+	return reservation, nil
+}
+
+func fillBalance(balance *dto.StayBalance) *dto.StayBalance {
+	paid := balance.Paid - balance.Refunded
+	balance.Balance = balance.Total - paid
+	switch {
+	case balance.Balance < 0:
+		balance.Status = "overpaid"
+	case balance.Balance == 0:
+		balance.Status = "paid"
+	case paid == 0:
+		balance.Status = "unpaid"
+	default:
+		balance.Status = "partial"
+	}
+	return balance
+}
+
+func matchCurrency(payment *model.Payment, currency string) error {
+	var err error
+	if payment.Currency == "" {
+		payment.Currency = currency
+		return nil
+	}
+	if payment.Currency, err = model.NormalizeCurrency(payment.Currency); err != nil {
+		return err
+	}
+	if payment.Currency != currency {
+		return errors.New("error CRMS : Payment currency does not match the stay")
+	}
+	return nil
+}
+
+func convertToSliceOfPayment(payments []*model.Payment) []*model.Payment {
+	var paymentsSlice []*model.Payment
+	for _, payment := range payments {
+		paymentsSlice = append(paymentsSlice, payment)
+	}
+	return paymentsSlice
+}
+
+func validatePaymentInfo(payment *model.Payment) error {
+	if payment.Kind != model.PaymentKindPayment && payment.Kind != model.PaymentKindRefund {
+		return errors.New("error CRMS : Payment kind is invalid")
+	}
+	valid := false
+	for _, method := range model.PaymentMethods {
+		if payment.Method == method {
+			valid = true
+		}
+	}
+	if !valid {
+		return errors.New("error CRMS : Payment method is invalid")
+	}
+	if payment.Amount <= 0 || payment.ReceivedBy == "" {
+		return errors.New("error CRMS : Payment Info is incomplete")
+	}
+	return nil
+}
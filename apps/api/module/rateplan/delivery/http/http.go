@@ -0,0 +1,336 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+type RatePlanHandler struct {
+	ser domain.RatePlanService
+}
+
+func NewRatePlanHandler(e *gin.Engine, ser domain.RatePlanService) {
+	handler := &RatePlanHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/ratePlanList", handler.ListRatePlans)
+		api.POST("/ratePlanId", handler.GetRatePlanById)
+		api.POST("/ratePlanCre", handler.CreateRatePlan)
+		api.POST("/ratePlanMod", handler.ModifyRatePlan)
+		api.POST("/ratePlanDel", handler.DeleteRatePlan)
+		api.POST("/ratePlanQuote", handler.QuoteStay)
+	}
+}
+
+// ListRatePlans @Summary ListRatePlans
+// @Description Get all RatePlans with their seasons
+// @Tags RatePlan
+// @Produce application/json
+// @Success 200 {object} []model.RatePlan
+// @Failure 500 {string} string "{"Message": "Internal Error!"}"
+// @Router /ratePlanList [post]
+func (u *RatePlanHandler) ListRatePlans(c *gin.Context) {
+	plans, err := u.ser.ListRatePlans()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": "Internal Error!",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":   "List all rate plans",
+		"ratePlans": plans,
+	})
+}
+
+// GetRatePlanById @Summary GetRatePlanById
+// @Description Get RatePlan by RatePlanId
+// @Tags RatePlan
+// @Produce application/json
+// @Param RatePlanId body dto.RatePlanIdRequest true "RatePlan id"
+// @Success 200 {object} model.RatePlan
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /ratePlanId [post]
+func (u *RatePlanHandler) GetRatePlanById(c *gin.Context) {
+	request := dto.RatePlanIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	plan, err := u.ser.GetRatePlanById(request.RatePlanId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this rate plan" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// CreateRatePlan @Summary CreateRatePlan
+// @Description Create a new RatePlan for a room type
+// @Tags RatePlan
+// @Accept json
+// @Produce application/json
+// @Param RatePlan body dto.RatePlanRequest true "RatePlan Information" example: {"Name": "Standard", "RoomType": "Double", "BaseOccupancy": 2, "WeekdayRate": 2400, "WeekendRate": 2800, "ExtraPersonRate": 500, "Active": true, "Seasons": [{"Name": "Summer", "StartDate": "2020-07-01", "EndDate": "2020-08-31", "WeekdayRate": 2800, "WeekendRate": 3200}]}
+// @Success 200 {object} model.RatePlan
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /ratePlanCre [post]
+func (u *RatePlanHandler) CreateRatePlan(c *gin.Context) {
+	request := dto.RatePlanRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	plan, err := transformToRatePlan(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	plan, err = u.ser.CreateRatePlan(plan)
+	if err != nil {
+		if err.Error() == "error CRMS : Rate plan Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Season ends before it starts" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// ModifyRatePlan @Summary ModifyRatePlan
+// @Description Modify RatePlan, replacing its seasons
+// @Tags RatePlan
+// @Accept json
+// @Produce application/json
+// @Param RatePlan body dto.RatePlanRequest true "RatePlan Information"
+// @Success 200 {object} model.RatePlan
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /ratePlanMod [post]
+func (u *RatePlanHandler) ModifyRatePlan(c *gin.Context) {
+	request := dto.RatePlanRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	plan, err := transformToRatePlan(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	plan, err = u.ser.UpdateRatePlan(plan)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this rate plan" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Rate plan Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Season ends before it starts" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// DeleteRatePlan @Summary DeleteRatePlan
+// @Description Delete RatePlan by RatePlanId, rate plans used by histories can only be deactivated
+// @Tags RatePlan
+// @Produce application/json
+// @Param RatePlanId body dto.RatePlanIdRequest true "RatePlan id"
+// @Success 200 {object} string "Message": "Delete success"
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /ratePlanDel [post]
+func (u *RatePlanHandler) DeleteRatePlan(c *gin.Context) {
+	request := dto.RatePlanIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	err := u.ser.DeleteRatePlan(request.RatePlanId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this rate plan" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This rate plan has histories, deactivate it instead" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "Delete success",
+	})
+}
+
+// QuoteStay @Summary QuoteStay
+// @Description Price a proposed stay night by night, with the cheapest active plan of the room type when RatePlanId is empty
+// @Tags RatePlan
+// @Accept json
+// @Produce application/json
+// @Param Quote body dto.RatePlanQuoteRequest true "Stay" example: {"Room": "201", "CheckIn": "2020-07-10", "CheckOut": "2020-07-12", "NumberOfPeople": 3}
+// @Success 200 {object} dto.StayQuote
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /ratePlanQuote [post]
+func (u *RatePlanHandler) QuoteStay(c *gin.Context) {
+	request := dto.RatePlanQuoteRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	checkIn, err := time.ParseInLocation("2006-01-02", request.CheckIn, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	checkOut, err := time.ParseInLocation("2006-01-02", request.CheckOut, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	room := &model.Room{
+		Id:     request.RoomId,
+		Number: request.Room,
+	}
+	quote, err := u.ser.QuoteStay(request.RatePlanId, uuid.Nil, room, checkIn, checkOut, request.NumberOfPeople)
+	if err != nil {
+		if err.Error() == "error CRMS : Quote Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Check-out date must be after check-in date" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this rate plan" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This rate plan is not for this room type" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This rate plan is not active" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no rate plan for this room type" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, quote)
+}
+
+func transformToRatePlan(requestData dto.RatePlanRequest) (*model.RatePlan, error) {
+	plan := &model.RatePlan{
+		Id:              requestData.RatePlanId,
+		Name:            requestData.Name,
+		RoomType:        requestData.RoomType,
+		BaseOccupancy:   requestData.BaseOccupancy,
+		WeekdayRate:     requestData.WeekdayRate,
+		WeekendRate:     requestData.WeekendRate,
+		ExtraPersonRate: requestData.ExtraPersonRate,
+		Active:          requestData.Active,
+		Note:            requestData.Note,
+	}
+	for _, season := range requestData.Seasons {
+		startDate, err := time.ParseInLocation("2006-01-02", season.StartDate, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		endDate, err := time.ParseInLocation("2006-01-02", season.EndDate, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		plan.Seasons = append(plan.Seasons, &model.RatePlanSeason{
+			Name:            season.Name,
+			StartDate:       startDate,
+			EndDate:         endDate,
+			WeekdayRate:     season.WeekdayRate,
+			WeekendRate:     season.WeekendRate,
+			ExtraPersonRate: season.ExtraPersonRate,
+		})
+	}
+	return plan, nil
+}
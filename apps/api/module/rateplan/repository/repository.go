@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RatePlanRepository struct {
+	orm *gorm.DB
+}
+
+func NewRatePlanRepository(orm *gorm.DB) domain.RatePlanRepository {
+	return &RatePlanRepository{
+		orm: orm,
+	}
+}
+
+func (u *RatePlanRepository) ListRatePlans() ([]*model.RatePlan, error) {
+	var plans []*model.RatePlan
+	err := u.orm.Preload("Seasons", orderSeasons).Order("RoomType").Order("Name").Find(&plans).Error
+	return plans, err
+}
+
+func (u *RatePlanRepository) ListRatePlansByRoomType(roomType string) ([]*model.RatePlan, error) {
+	var plans []*model.RatePlan
+	err := u.orm.Preload("Seasons", orderSeasons).Where("RoomType = ? AND Active = ?", roomType, true).Order("Name").Find(&plans).Error
+	return plans, err
+}
+
+func (u *RatePlanRepository) GetRatePlanById(plan *model.RatePlan) (*model.RatePlan, error) {
+	err := u.orm.Preload("Seasons", orderSeasons).Where("Id = ?", plan.Id).Find(&plan).Error
+	return plan, err
+}
+
+func (u *RatePlanRepository) CreateRatePlan(plan *model.RatePlan) (*model.RatePlan, error) {
+	err := u.orm.Create(&plan).Error
+	return plan, err
+}
+
+func (u *RatePlanRepository) UpdateRatePlan(plan *model.RatePlan) (*model.RatePlan, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(plan).Omit(clause.Associations).Where("Id = ?", plan.Id).Select("*").Updates(&plan).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("RatePlanId = ?", plan.Id).Delete(&model.RatePlanSeason{}).Error; err != nil {
+			return err
+		}
+		if len(plan.Seasons) == 0 {
+			return nil
+		}
+		return tx.Create(plan.Seasons).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetRatePlanById(plan)
+}
+
+func (u *RatePlanRepository) DeleteRatePlan(plan *model.RatePlan) error {
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("RatePlanId = ?", plan.Id).Delete(&model.RatePlanSeason{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("Id = ?", plan.Id).Delete(&model.RatePlan{}).Error
+	})
+}
+
+func (u *RatePlanRepository) CountHistoriesByRatePlan(plan *model.RatePlan) (int64, error) {
+	var count int64
+	err := u.orm.Model(&model.History{}).Where("RatePlanId = ?", plan.Id).Count(&count).Error
+	return count, err
+}
+
+func (u *RatePlanRepository) ConfirmRoomExistence(room *model.Room) (*model.Room, error) {
+	var err error
+	if room.Id != uuid.Nil {
+		err = u.orm.Where("Id = ?", room.Id).Find(&room).Error
+	} else {
+		err = u.orm.Where("Number = ?", room.Number).Find(&room).Error
+	}
+	return room, err
+}
+
+func orderSeasons(db *gorm.DB) *gorm.DB {
+	return db.Order("StartDate")
+}
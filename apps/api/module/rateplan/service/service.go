@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+type RatePlanService struct {
+	repo domain.RatePlanRepository
+}
+
+func NewRatePlanService(repo domain.RatePlanRepository) domain.RatePlanService {
+	return &RatePlanService{
+		repo: repo,
+	}
+}
+
+func (u *RatePlanService) ListRatePlans() ([]*model.RatePlan, error) {
+	var err error
+	var plans []*model.RatePlan
+	if plans, err = u.repo.ListRatePlans(); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfRatePlan(plans), err
+}
+
+func (u *RatePlanService) GetRatePlanById(planId uuid.UUID) (*model.RatePlan, error) {
+	var err error
+	newPlan := &model.RatePlan{
+		Id: planId,
+	}
+	if newPlan, err = u.repo.GetRatePlanById(newPlan); err != nil {
+		return nil, err
+	} else if newPlan.Name == "" {
+		return nil, errors.New("error CRMS : There is no this rate plan")
+	}
+	return newPlan, err
+}
+
+func (u *RatePlanService) CreateRatePlan(plan *model.RatePlan) (*model.RatePlan, error) {
+	var err error
+	if err = validateRatePlanInfo(plan); err != nil {
+		return nil, err
+	}
+	plan.Id = uuid.New()
+	assignSeasons(plan)
+	return u.repo.CreateRatePlan(plan)
+}
+
+func (u *RatePlanService) UpdateRatePlan(plan *model.RatePlan) (*model.RatePlan, error) {
+	var err error
+	if _, err = u.GetRatePlanById(plan.Id); err != nil {
+		return nil, err
+	}
+	if err = validateRatePlanInfo(plan); err != nil {
+		return nil, err
+	}
+	assignSeasons(plan)
+	return u.repo.UpdateRatePlan(plan)
+}
+
+func (u *RatePlanService) DeleteRatePlan(planId uuid.UUID) error {
+	var err error
+	var plan *model.RatePlan
+	var count int64
+	if plan, err = u.GetRatePlanById(planId); err != nil {
+		return err
+	}
+	if count, err = u.repo.CountHistoriesByRatePlan(plan); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This rate plan has histories, deactivate it instead")
+	}
+	return u.repo.DeleteRatePlan(plan)
+}
+
+// QuoteStay prices the stay with planId, or with the cheapest active plan of the room type when planId is empty. An
+// inactive plan only prices a stay already on it, currentPlanId, so stays can still be corrected after it is retired.
+func (u *RatePlanService) QuoteStay(planId uuid.UUID, currentPlanId uuid.UUID, room *model.Room, checkIn time.Time, checkOut time.Time, numberOfPeople int) (*dto.StayQuote, error) {
+	var err error
+	var plans []*model.RatePlan
+	var quote *dto.StayQuote
+
+	if checkIn.IsZero() || checkOut.IsZero() || numberOfPeople <= 0 {
+		return nil, errors.New("error CRMS : Quote Info is incomplete")
+	}
+	if !checkOut.After(checkIn) {
+		return nil, errors.New("error CRMS : Check-out date must be after check-in date")
+	}
+	if room.Type == "" {
+		room.Number = model.NormalizeRoomNumber(room.Number)
+		if room.Id == uuid.Nil && room.Number == "" {
+			return nil, errors.New("error CRMS : Quote Info is incomplete")
+		}
+		if room, err = u.repo.ConfirmRoomExistence(room); err != nil {
+			return nil, err
+		} else if room.Id == uuid.Nil {
+			return nil, errors.New("error CRMS : There is no this room")
+		}
+	}
+
+	if planId != uuid.Nil {
+		var plan *model.RatePlan
+		if plan, err = u.GetRatePlanById(planId); err != nil {
+			return nil, err
+		}
+		if plan.RoomType != room.Type {
+			return nil, errors.New("error CRMS : This rate plan is not for this room type")
+		}
+		if !plan.Active && plan.Id != currentPlanId {
+			return nil, errors.New("error CRMS : This rate plan is not active")
+		}
+		plans = []*model.RatePlan{plan}
+	} else if plans, err = u.repo.ListRatePlansByRoomType(room.Type); err != nil {
+		return nil, err
+	} else if len(plans) == 0 {
+		return nil, domain.ErrNoRatePlan
+	}
+
+	for _, plan := range plans {
+		current := priceStay(plan, checkIn, checkOut, numberOfPeople)
+		if quote == nil || current.Total < quote.Total {
+			quote = current
+		}
+	}
+	quote.Room = room
+	return quote, nil
+}
+
+// priceStay prices every night of the stay with the season covering it, or with the plan when no season does
+func priceStay(plan *model.RatePlan, checkIn time.Time, checkOut time.Time, numberOfPeople int) *dto.StayQuote {
+	quote := &dto.StayQuote{
+		RatePlan:       plan,
+		CheckIn:        checkIn.Format("2006-01-02"),
+		CheckOut:       checkOut.Format("2006-01-02"),
+		NumberOfPeople: numberOfPeople,
+	}
+	extraPeople := numberOfPeople - plan.BaseOccupancy
+	if extraPeople < 0 {
+		extraPeople = 0
+	}
+	for date := checkIn; date.Before(checkOut); date = date.AddDate(0, 0, 1) {
+		night := &dto.QuoteNight{
+			Date:        date.Format("2006-01-02"),
+			Weekend:     model.IsWeekendNight(date),
+			Rate:        plan.WeekdayRate,
+			ExtraPeople: extraPeople,
+		}
+		extraRate := plan.ExtraPersonRate
+		if night.Weekend {
+			night.Rate = plan.WeekendRate
+		}
+		if season := seasonOf(plan, date); season != nil {
+			night.Season = season.Name
+			if !night.Weekend && season.WeekdayRate > 0 {
+				night.Rate = season.WeekdayRate
+			} else if night.Weekend && season.WeekendRate > 0 {
+				night.Rate = season.WeekendRate
+			}
+			if season.ExtraPersonRate > 0 {
+				extraRate = season.ExtraPersonRate
+			}
+		}
+		night.ExtraCharge = extraPeople * extraRate
+		night.Amount = night.Rate + night.ExtraCharge
+		quote.Nights = append(quote.Nights, night)
+		quote.Total += night.Amount
+	}
+	return quote
+}
+
+// seasonOf returns the shortest season covering the night, so a holiday inside a summer season wins
+func seasonOf(plan *model.RatePlan, date time.Time) *model.RatePlanSeason {
+	var found *model.RatePlanSeason
+	for _, season := range plan.Seasons {
+		if !season.Covers(date) {
+			continue
+		}
+		if found == nil || season.EndDate.Sub(season.StartDate) < found.EndDate.Sub(found.StartDate) {
+			found = season
+		}
+	}
+	return found
+}
+
+func assignSeasons(plan *model.RatePlan) {
+	for _, season := range plan.Seasons {
+		season.Id = uuid.New()
+		season.RatePlanId = plan.Id
+	}
+}
+
+func convertToSliceOfRatePlan(plans []*model.RatePlan) []*model.RatePlan {
+	var plansSlice []*model.RatePlan
+	for _, plan := range plans {
+		plansSlice = append(plansSlice, plan)
+	}
+	return plansSlice
+}
+
+func validateRatePlanInfo(plan *model.RatePlan) error {
+	if plan.Name == "" || plan.RoomType == "" {
+		return errors.New("error CRMS : Rate plan Info is incomplete")
+	}
+	if plan.BaseOccupancy <= 0 {
+		return errors.New("error CRMS : Rate plan Info is incomplete")
+	}
+	if plan.WeekdayRate < 0 || plan.WeekendRate < 0 || plan.ExtraPersonRate < 0 {
+		return errors.New("error CRMS : Rate plan Info is incomplete")
+	}
+	for _, season := range plan.Seasons {
+		if season.Name == "" || season.StartDate.IsZero() || season.EndDate.IsZero() {
+			return errors.New("error CRMS : Rate plan Info is incomplete")
+		}
+		if season.EndDate.Before(season.StartDate) {
+			return errors.New("error CRMS : Season ends before it starts")
+		}
+		if season.WeekdayRate < 0 || season.WeekendRate < 0 || season.ExtraPersonRate < 0 {
+			return errors.New("error CRMS : Rate plan Info is incomplete")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/config"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/S1nceU/CRMS/apps/api/sheet"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// registrationValues are the fields a registration layout can place in its columns
+var registrationValues = map[string]func(r *dto.RegistrationRow) interface{}{
+	"Name":           func(r *dto.RegistrationRow) interface{} { return r.Name },
+	"PassportNumber": func(r *dto.RegistrationRow) interface{} { return r.PassportNumber },
+	"Nationality":    func(r *dto.RegistrationRow) interface{} { return r.Nationality },
+	"Nation":         func(r *dto.RegistrationRow) interface{} { return r.Nation },
+	"Birthday":       func(r *dto.RegistrationRow) interface{} { return r.Birthday },
+	"Gender":         func(r *dto.RegistrationRow) interface{} { return r.Gender },
+	"CheckIn":        func(r *dto.RegistrationRow) interface{} { return r.CheckIn },
+	"CheckOut":       func(r *dto.RegistrationRow) interface{} { return r.CheckOut },
+	"Room":           func(r *dto.RegistrationRow) interface{} { return r.Room },
+}
+
+type RegistrationHandler struct {
+	ser domain.RegistrationService
+}
+
+func NewRegistrationHandler(e *gin.Engine, ser domain.RegistrationService) {
+	handler := &RegistrationHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/registrationList", handler.ListRegistrations)
+		api.POST("/registrationExport", handler.ExportRegistrations)
+		api.POST("/registrationSubmit", handler.SubmitRegistrations)
+	}
+}
+
+// ListRegistrations @Summary ListRegistrations
+// @Description Get the guests in house on a night who are not domestic citizens, companions included, with the required fields they are missing and when they were reported
+// @Tags Registration
+// @Accept json
+// @Produce application/json
+// @Param Registration body dto.RegistrationRequest true "Night and whether to leave out reported guests" example: {"Date": "2020-01-01", "Unsubmitted": true}
+// @Success 200 {object} []dto.RegistrationRow
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /registrationList [post]
+func (u *RegistrationHandler) ListRegistrations(c *gin.Context) {
+	request := dto.RegistrationRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	registrations, err := u.ser.ListRegistrations(request.Date, request.Unsubmitted)
+	if err != nil {
+		u.respondRegistrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":       "List foreign guests of the night",
+		"registrations": registrations,
+	})
+}
+
+// ExportRegistrations @Summary ExportRegistrations
+// @Description Download the foreign guests of a night not reported yet, in the CSV or fixed-width layout configured for the authorities. Guests missing a required field are left out. Record the guests as reported with registrationSubmit once the file is sent
+// @Tags Registration
+// @Accept json
+// @Produce application/octet-stream
+// @Param Export body dto.RegistrationExportRequest true "Night and whether to report reported guests again" example: {"Date": "2020-01-01", "Resubmit": false}
+// @Success 200 {file} file
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /registrationExport [post]
+func (u *RegistrationHandler) ExportRegistrations(c *gin.Context) {
+	request := dto.RegistrationExportRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	layout := config.RegistrationLayout()
+	columns, widths, err := registrationColumns(layout)
+	if err != nil {
+		u.respondRegistrationError(c, err)
+		return
+	}
+	registrations, err := u.ser.ExportRegistrations(request.Date, request.Resubmit)
+	if err != nil {
+		u.respondRegistrationError(c, err)
+		return
+	}
+	contentType, extension, _ := sheet.ContentType(layout.Format)
+	keys, labels := sheet.Header(columns, "en")
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename=registrations-"+request.Date+extension)
+	c.Status(http.StatusOK)
+	var writer sheet.Writer
+	if layout.Format == model.RegistrationFormatFixed {
+		writer = sheet.NewFixedWidthWriter(c.Writer, widths)
+	} else if writer, err = sheet.NewWriter(layout.Format, c.Writer); err != nil {
+		log.Println("Export registrations failed:", err)
+		return
+	}
+	err = writer.WriteHeader(keys, labels)
+	for _, registration := range registrations {
+		if err != nil {
+			break
+		}
+		err = writer.WriteRow(sheet.Values(columns, registration))
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		log.Println("Export registrations failed:", err)
+	}
+}
+
+// SubmitRegistrations @Summary SubmitRegistrations
+// @Description Record foreign guests of a night as reported to the authorities, after their file was sent. Every guest must have the required fields
+// @Tags Registration
+// @Accept json
+// @Produce application/json
+// @Param Submit body dto.RegistrationSubmitRequest true "Night and guests reported" example: {"Date": "2020-01-01", "Guests": [{"HistoryId": "00000000-0000-0000-0000-000000000000", "CustomerId": "00000000-0000-0000-0000-000000000000"}]}
+// @Success 200 {object} []dto.RegistrationRow
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /registrationSubmit [post]
+func (u *RegistrationHandler) SubmitRegistrations(c *gin.Context) {
+	request := dto.RegistrationSubmitRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	registrations, err := u.ser.SubmitRegistrations(request.Date, request.Guests)
+	if err != nil {
+		u.respondRegistrationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":       "Foreign guests recorded as reported",
+		"registrations": registrations,
+	})
+}
+
+// registrationColumns turns a registration layout into sheet columns and their widths, checking every field is known
+// and every fixed-width column has a width
+func registrationColumns(layout model.RegistrationLayout) ([]sheet.Column[*dto.RegistrationRow], []int, error) {
+	if layout.Format != model.RegistrationFormatCSV && layout.Format != model.RegistrationFormatFixed {
+		return nil, nil, errors.New("error CRMS : Registration layout is invalid")
+	}
+	var columns []sheet.Column[*dto.RegistrationRow]
+	var widths []int
+	for _, field := range layout.Fields {
+		value, ok := registrationValues[field.Field]
+		if !ok || (layout.Format == model.RegistrationFormatFixed && field.Width <= 0) {
+			return nil, nil, errors.New("error CRMS : Registration layout is invalid")
+		}
+		label := field.Label
+		if strings.TrimSpace(label) == "" {
+			label = field.Field
+		}
+		columns = append(columns, sheet.Column[*dto.RegistrationRow]{Key: field.Field, Labels: map[string]string{"en": label}, Value: value})
+		widths = append(widths, field.Width)
+	}
+	if len(columns) == 0 {
+		return nil, nil, errors.New("error CRMS : Registration layout is invalid")
+	}
+	return columns, widths, nil
+}
+
+func (u *RegistrationHandler) respondRegistrationError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "error CRMS : Date is incomplete",
+		"error CRMS : There is no guest to report",
+		"error CRMS : This guest is not a foreign guest of this night",
+		"error CRMS : This guest is missing required fields",
+		"error CRMS : Registration layout is invalid":
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+	}
+}
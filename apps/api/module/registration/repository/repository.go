@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type RegistrationRepository struct {
+	orm *gorm.DB
+}
+
+func NewRegistrationRepository(orm *gorm.DB) domain.RegistrationRepository {
+	return &RegistrationRepository{
+		orm: orm,
+	}
+}
+
+// ListRegistrationGuests lists every guest of the stays in house the night of date, companions included, in the room
+// of their segment that night. Guests without a citizenship are listed so that they can be completed.
+func (u *RegistrationRepository) ListRegistrationGuests(domestic string, date time.Time) ([]*dto.RegistrationRow, error) {
+	var rows []*dto.RegistrationRow
+	err := u.orm.Raw(`SELECT g.HistoryId, g.CustomerId, c.Name, c.NationalId AS PassportNumber,
+			COALESCE(n.Alpha3, '') AS Nationality, COALESCE(n.Nation, '') AS Nation, c.Birthday, c.Gender,
+			h.CheckIn, h.CheckOut, r.Number AS Room, s.SubmittedAt
+		FROM stay_guests g
+			JOIN histories h ON h.Id = g.HistoryId
+			JOIN customers c ON c.Id = g.CustomerId
+			LEFT JOIN citizenships n ON n.Id = c.CitizenshipId
+			JOIN stay_segments sg ON sg.HistoryId = h.Id AND sg.CheckIn <= @date AND sg.CheckOut > @date
+			JOIN rooms r ON r.Id = sg.RoomId
+			LEFT JOIN registration_submissions s ON s.HistoryId = g.HistoryId AND s.CustomerId = g.CustomerId
+		WHERE h.Kind = @stay AND (n.Alpha3 IS NULL OR n.Alpha3 <> @domestic)
+		ORDER BY r.Number, g.IsPrimary DESC, c.Name`,
+		map[string]interface{}{
+			"stay":     model.HistoryKindStay,
+			"domestic": domestic,
+			"date":     date,
+		}).Scan(&rows).Error
+	return rows, err
+}
+
+func (u *RegistrationRepository) CreateRegistrationSubmissions(submissions []*model.RegistrationSubmission) error {
+	return u.orm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "HistoryId"}, {Name: "CustomerId"}},
+		DoUpdates: clause.AssignmentColumns([]string{"Date", "SubmittedAt"}),
+	}).Create(&submissions).Error
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/config"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+type RegistrationService struct {
+	repo domain.RegistrationRepository
+}
+
+func NewRegistrationService(repo domain.RegistrationRepository) domain.RegistrationService {
+	return &RegistrationService{
+		repo: repo,
+	}
+}
+
+// ListRegistrations lists the guests of the stays in house the night of date who are not domestic citizens, flagging
+// the fields the authorities require that are missing
+func (u *RegistrationService) ListRegistrations(date string, unsubmitted bool) ([]*dto.RegistrationRow, error) {
+	var err error
+	var night time.Time
+	var rows []*dto.RegistrationRow
+	if night, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+		return nil, errors.New("error CRMS : Date is incomplete")
+	}
+	if rows, err = u.repo.ListRegistrationGuests(config.DomesticCitizenship(), night); err != nil {
+		return nil, err
+	}
+	registrations := make([]*dto.RegistrationRow, 0, len(rows))
+	for _, row := range rows {
+		if unsubmitted && row.SubmittedAt != nil {
+			continue
+		}
+		row.Missing = missingRegistrationFields(row)
+		registrations = append(registrations, row)
+	}
+	return registrations, nil
+}
+
+// ExportRegistrations returns the foreign guests of the night of date with every required field, those already
+// reported only when resubmitting. They are recorded as reported by SubmitRegistrations once the file is sent.
+func (u *RegistrationService) ExportRegistrations(date string, resubmit bool) ([]*dto.RegistrationRow, error) {
+	var err error
+	var rows []*dto.RegistrationRow
+	if rows, err = u.ListRegistrations(date, !resubmit); err != nil {
+		return nil, err
+	}
+	var registrations []*dto.RegistrationRow
+	for _, row := range rows {
+		if len(row.Missing) == 0 {
+			registrations = append(registrations, row)
+		}
+	}
+	if len(registrations) == 0 {
+		return nil, errors.New("error CRMS : There is no guest to report")
+	}
+	return registrations, nil
+}
+
+// SubmitRegistrations records as reported the given guests, which must be foreign guests of the night of date with
+// every required field
+func (u *RegistrationService) SubmitRegistrations(date string, guests []dto.RegistrationGuest) ([]*dto.RegistrationRow, error) {
+	var err error
+	var rows []*dto.RegistrationRow
+	if len(guests) == 0 {
+		return nil, errors.New("error CRMS : There is no guest to report")
+	}
+	if rows, err = u.ListRegistrations(date, false); err != nil {
+		return nil, err
+	}
+	night, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+	now := time.Now()
+	var registrations []*dto.RegistrationRow
+	var submissions []*model.RegistrationSubmission
+	for _, guest := range guests {
+		row := findRegistration(rows, guest)
+		if row == nil {
+			return nil, errors.New("error CRMS : This guest is not a foreign guest of this night")
+		}
+		if len(row.Missing) != 0 {
+			return nil, errors.New("error CRMS : This guest is missing required fields")
+		}
+		row.SubmittedAt = &now
+		registrations = append(registrations, row)
+		submissions = append(submissions, &model.RegistrationSubmission{
+			Id:          uuid.New(),
+			HistoryId:   row.HistoryId,
+			CustomerId:  row.CustomerId,
+			Date:        night,
+			SubmittedAt: now,
+		})
+	}
+	if err = u.repo.CreateRegistrationSubmissions(submissions); err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+func findRegistration(rows []*dto.RegistrationRow, guest dto.RegistrationGuest) *dto.RegistrationRow {
+	for _, row := range rows {
+		if row.HistoryId == guest.HistoryId && row.CustomerId == guest.CustomerId {
+			return row
+		}
+	}
+	return nil
+}
+
+// missingRegistrationFields returns the fields of a guest required by the authorities that are empty
+func missingRegistrationFields(row *dto.RegistrationRow) []string {
+	missing := []string{}
+	if row.Name == "" {
+		missing = append(missing, "Name")
+	}
+	if row.PassportNumber == "" {
+		missing = append(missing, "PassportNumber")
+	}
+	if row.Nationality == "" {
+		missing = append(missing, "Nationality")
+	}
+	if row.Birthday.IsZero() {
+		missing = append(missing, "Birthday")
+	}
+	return missing
+}
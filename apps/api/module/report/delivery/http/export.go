@@ -0,0 +1,73 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/S1nceU/CRMS/apps/api/sheet"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+)
+
+// nationalityColumns is the layout of the monthly guest statistics of the tourism bureau
+var nationalityColumns = []sheet.Column[*dto.NationalityRow]{
+	{Key: "Alpha3", Labels: map[string]string{"en": "Country Code", "zh-TW": "國家代碼"}, Value: func(r *dto.NationalityRow) interface{} { return r.Alpha3 }},
+	{Key: "Nation", Labels: map[string]string{"en": "Nationality", "zh-TW": "國籍"}, Value: func(r *dto.NationalityRow) interface{} { return r.Nation }},
+	{Key: "Guests", Labels: map[string]string{"en": "Guests", "zh-TW": "住客人數"}, Value: func(r *dto.NationalityRow) interface{} { return r.Guests }},
+	{Key: "GuestNights", Labels: map[string]string{"en": "Guest Nights", "zh-TW": "住客人夜數"}, Value: func(r *dto.NationalityRow) interface{} { return r.GuestNights }},
+}
+
+// ExportNationalityReport @Summary ExportNationalityReport
+// @Description Download the guests and guest-nights per citizenship of a range as CSV in the layout of the tourism bureau, with a last row of totals
+// @Tags Report
+// @Accept json
+// @Produce text/csv
+// @Param Export body dto.NationalityExportRequest true "Range and header language" example: {"startDate": "2020-01-01", "endDate": "2020-01-31", "Language": "zh-TW"}
+// @Success 200 {file} file
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reportNationalityExport [post]
+func (u *ReportHandler) ExportNationalityReport(c *gin.Context) {
+	request := dto.NationalityExportRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	report, err := u.ser.NationalityReport(&request.NationalityReportRequest)
+	if err != nil {
+		u.respondReportError(c, err)
+		return
+	}
+	contentType, extension, _ := sheet.ContentType("csv")
+	keys, labels := sheet.Header(nationalityColumns, request.Language)
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename=nationalities-"+report.StartDate+"-"+report.EndDate+extension)
+	c.Status(http.StatusOK)
+	writer, err := sheet.NewWriter("csv", c.Writer)
+	if err == nil {
+		err = writer.WriteHeader(keys, labels)
+	}
+	for _, row := range report.Rows {
+		if err != nil {
+			break
+		}
+		err = writer.WriteRow(sheet.Values(nationalityColumns, row))
+	}
+	if err == nil {
+		err = writer.WriteRow(sheet.Values(nationalityColumns, &dto.NationalityRow{Nation: totalLabel(request.Language), NationalityFigures: report.Total}))
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		log.Println("Export nationality report failed:", err)
+	}
+}
+
+func totalLabel(language string) string {
+	if language == "zh-TW" {
+		return "合計"
+	}
+	return "Total"
+}
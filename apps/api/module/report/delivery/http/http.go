@@ -0,0 +1,225 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ReportHandler struct {
+	ser domain.ReportService
+}
+
+func NewReportHandler(e *gin.Engine, ser domain.ReportService) {
+	handler := &ReportHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/reportRevenue", handler.RevenueReport)
+		api.POST("/reportKpi", handler.KpiReport)
+		api.POST("/reportNationality", handler.NationalityReport)
+		api.POST("/reportNationalityExport", handler.ExportNationalityReport)
+		api.POST("/reportCustomerValue", handler.CustomerValue)
+		api.POST("/reportTopGuests", handler.TopGuests)
+		api.POST("/reportRepeatRate", handler.RepeatRateReport)
+		api.POST("/reportForecast", handler.Forecast)
+	}
+}
+
+// RevenueReport @Summary RevenueReport
+// @Description Get revenue, stays and guests per day, month or year, by room and by citizenship, compared with the periods before. Revenue is spread over the nights of each stay, stays and guests count in the period of their check-in and other currencies are summed apart
+// @Tags Report
+// @Accept json
+// @Produce application/json
+// @Param Report body dto.RevenueReportRequest true "Range, period and currency" example: {"startDate": "2020-01-01", "endDate": "2020-12-31", "Period": "month", "Currency": ""}
+// @Success 200 {object} dto.RevenueReport
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reportRevenue [post]
+func (u *ReportHandler) RevenueReport(c *gin.Context) {
+	request := dto.RevenueReportRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	report, err := u.ser.RevenueReport(&request)
+	if err != nil {
+		u.respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// KpiReport @Summary KpiReport
+// @Description Get the occupancy rate, average daily rate and revenue per available room of a range, in total and per night. Rooms out of service are left out of the inventory
+// @Tags Report
+// @Accept json
+// @Produce application/json
+// @Param Report body dto.KpiReportRequest true "Range, room type and currency" example: {"startDate": "2020-01-01", "endDate": "2020-01-31", "RoomType": "", "Currency": ""}
+// @Success 200 {object} dto.KpiReport
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reportKpi [post]
+func (u *ReportHandler) KpiReport(c *gin.Context) {
+	request := dto.KpiReportRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	report, err := u.ser.KpiReport(&request)
+	if err != nil {
+		u.respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// NationalityReport @Summary NationalityReport
+// @Description Get the unique guests and guest-nights per citizenship of a range. Every guest of a stay counts, companions included
+// @Tags Report
+// @Accept json
+// @Produce application/json
+// @Param Report body dto.NationalityReportRequest true "Range" example: {"startDate": "2020-01-01", "endDate": "2020-01-31"}
+// @Success 200 {object} dto.NationalityReport
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reportNationality [post]
+func (u *ReportHandler) NationalityReport(c *gin.Context) {
+	request := dto.NationalityReportRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	report, err := u.ser.NationalityReport(&request)
+	if err != nil {
+		u.respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// CustomerValue @Summary CustomerValue
+// @Description Get the number of stays, nights, spend, average spend per stay and first and last stay of a customer over all their Histories
+// @Tags Report
+// @Accept json
+// @Produce application/json
+// @Param Report body dto.CustomerValueRequest true "Customer and currency" example: {"CustomerId": "00000000-0000-0000-0000-000000000000", "Currency": ""}
+// @Success 200 {object} dto.CustomerValue
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reportCustomerValue [post]
+func (u *ReportHandler) CustomerValue(c *gin.Context) {
+	request := dto.CustomerValueRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	value, err := u.ser.CustomerValue(&request)
+	if err != nil {
+		u.respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, value)
+}
+
+// TopGuests @Summary TopGuests
+// @Description Get the customers with the highest spend, number of stays or nights, over the stays checking in within the range or over every stay
+// @Tags Report
+// @Accept json
+// @Produce application/json
+// @Param Report body dto.TopGuestsRequest true "Range, ranking, limit and currency" example: {"startDate": "", "endDate": "", "OrderBy": "spend", "Limit": 10, "Currency": ""}
+// @Success 200 {object} dto.TopGuests
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reportTopGuests [post]
+func (u *ReportHandler) TopGuests(c *gin.Context) {
+	request := dto.TopGuestsRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	guests, err := u.ser.TopGuests(&request)
+	if err != nil {
+		u.respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, guests)
+}
+
+// RepeatRateReport @Summary RepeatRateReport
+// @Description Get per day, month or year the share of guests checking in who have stayed at least twice by the end of the period
+// @Tags Report
+// @Accept json
+// @Produce application/json
+// @Param Report body dto.RepeatRateRequest true "Range and period" example: {"startDate": "2020-01-01", "endDate": "2020-12-31", "Period": "month"}
+// @Success 200 {object} dto.RepeatRateReport
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reportRepeatRate [post]
+func (u *ReportHandler) RepeatRateReport(c *gin.Context) {
+	request := dto.RepeatRateRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	report, err := u.ser.RepeatRateReport(&request)
+	if err != nil {
+		u.respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Forecast @Summary Forecast
+// @Description Get the occupancy and room revenue of every coming night: on the books from stays in house and confirmed reservations, the pickup expected from the pace of the same night 52 weeks ago, and the projection adding both
+// @Tags Report
+// @Accept json
+// @Produce application/json
+// @Param Forecast body dto.ForecastRequest true "Nights to forecast" example: {"Days": 90}
+// @Success 200 {object} dto.Forecast
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reportForecast [post]
+func (u *ReportHandler) Forecast(c *gin.Context) {
+	request := dto.ForecastRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	forecast, err := u.ser.Forecast(&request)
+	if err != nil {
+		u.respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, forecast)
+}
+
+func (u *ReportHandler) respondReportError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "error CRMS : Date is incomplete",
+		"error CRMS : Start date is after end date",
+		"error CRMS : Date range is too long",
+		"error CRMS : Period must be day, month or year",
+		"error CRMS : Currency is invalid",
+		"error CRMS : Order must be spend, stays or nights",
+		"error CRMS : Limit must be between 1 and 100",
+		"error CRMS : Days must be between 1 and 366",
+		"error CRMS : There is no this customer":
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+	}
+}
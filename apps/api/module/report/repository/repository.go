@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// revenueNights spreads every history checking in before end over its nights from start to end, the way the KPI
+// report spreads room charges. A fee without nights falls on its check-in date.
+const revenueNights = `WITH RECURSIVE nights (HistoryId, Night, FirstNight, Nights) AS (
+		SELECT h.Id, GREATEST(CAST(h.CheckIn AS DATE), CAST(@start AS DATE)), CAST(h.CheckIn AS DATE), GREATEST(DATEDIFF(h.CheckOut, h.CheckIn), 1)
+		FROM histories h
+		WHERE h.CheckIn < @end AND CAST(h.CheckIn AS DATE) + INTERVAL GREATEST(DATEDIFF(h.CheckOut, h.CheckIn), 1) DAY > @start
+		UNION ALL
+		SELECT HistoryId, Night + INTERVAL 1 DAY, FirstNight, Nights FROM nights
+		WHERE Night + INTERVAL 1 DAY < FirstNight + INTERVAL Nights DAY AND Night + INTERVAL 1 DAY < @end
+	)
+	`
+
+// revenueColumns sums the figures of a revenue report over the nights n of histories h. Each night carries its share
+// of the amounts of the history; a stay and its guests count on the night it checks in. Fees count as revenue only.
+const revenueColumns = `CAST(ROUND(COALESCE(SUM(h.TotalAmount / n.Nights), 0)) AS SIGNED) AS Revenue,
+	CAST(ROUND(COALESCE(SUM((h.TotalAmount - h.TaxAmount) / n.Nights), 0)) AS SIGNED) AS NetRevenue,
+	COALESCE(SUM(CASE WHEN h.Kind = @stay AND n.Night = n.FirstNight THEN 1 ELSE 0 END), 0) AS Stays,
+	COALESCE(SUM(CASE WHEN h.Kind = @stay AND n.Night = n.FirstNight THEN h.NumberOfPeople ELSE 0 END), 0) AS Guests`
+
+// customerValueColumns sums the figures of a customer over histories h. Fees count as spend only.
+const customerValueColumns = `COALESCE(SUM(CASE WHEN h.Kind = @stay THEN 1 ELSE 0 END), 0) AS Stays,
+	COALESCE(SUM(CASE WHEN h.Kind = @stay THEN DATEDIFF(h.CheckOut, h.CheckIn) ELSE 0 END), 0) AS Nights,
+	COALESCE(SUM(CASE WHEN h.Currency = @currency THEN h.TotalAmount ELSE 0 END), 0) AS Spend,
+	COALESCE(DATE_FORMAT(MIN(CASE WHEN h.Kind = @stay THEN h.CheckIn END), '%Y-%m-%d'), '') AS FirstStay,
+	COALESCE(DATE_FORMAT(MAX(CASE WHEN h.Kind = @stay THEN h.CheckIn END), '%Y-%m-%d'), '') AS LastStay`
+
+type ReportRepository struct {
+	orm *gorm.DB
+}
+
+func NewReportRepository(orm *gorm.DB) domain.ReportRepository {
+	return &ReportRepository{
+		orm: orm,
+	}
+}
+
+func (u *ReportRepository) SumRevenue(currency string, start time.Time, end time.Time) (*dto.RevenueFigures, error) {
+	figures := &dto.RevenueFigures{}
+	err := u.orm.Raw(revenueNights+`SELECT `+revenueColumns+`
+		FROM nights n JOIN histories h ON h.Id = n.HistoryId
+		WHERE h.Currency = @currency`,
+		revenueArgs(currency, start, end)).Scan(figures).Error
+	return figures, err
+}
+
+func (u *ReportRepository) SumRevenueByPeriod(format string, currency string, start time.Time, end time.Time) ([]*dto.RevenuePeriod, error) {
+	var periods []*dto.RevenuePeriod
+	args := revenueArgs(currency, start, end)
+	args["format"] = format
+	err := u.orm.Raw(revenueNights+`SELECT DATE_FORMAT(n.Night, @format) AS Period, `+revenueColumns+`
+		FROM nights n JOIN histories h ON h.Id = n.HistoryId
+		WHERE h.Currency = @currency
+		GROUP BY Period
+		ORDER BY Period`,
+		args).Scan(&periods).Error
+	return periods, err
+}
+
+// SumRevenueByRoom credits each night of a stay that moved rooms to the room of its segment on that night
+func (u *ReportRepository) SumRevenueByRoom(currency string, start time.Time, end time.Time) ([]*dto.RevenueBreakdown, error) {
+	var rooms []*dto.RevenueBreakdown
+	err := u.orm.Raw(revenueNights+`SELECT r.Number AS Name, `+revenueColumns+`
+		FROM nights n JOIN histories h ON h.Id = n.HistoryId
+			LEFT JOIN stay_segments s ON s.HistoryId = h.Id AND s.CheckIn <= n.Night AND s.CheckOut > n.Night
+			JOIN rooms r ON r.Id = COALESCE(s.RoomId, h.RoomId)
+		WHERE h.Currency = @currency
+		GROUP BY r.Number
+		ORDER BY Revenue DESC, r.Number`,
+		revenueArgs(currency, start, end)).Scan(&rooms).Error
+	return rooms, err
+}
+
+func (u *ReportRepository) SumRevenueByCitizenship(currency string, start time.Time, end time.Time) ([]*dto.RevenueBreakdown, error) {
+	var citizenships []*dto.RevenueBreakdown
+	err := u.orm.Raw(revenueNights+`SELECT cz.Nation AS Name, `+revenueColumns+`
+		FROM nights n JOIN histories h ON h.Id = n.HistoryId JOIN customers c ON c.Id = h.CustomerId JOIN citizenships cz ON cz.Id = c.CitizenshipId
+		WHERE h.Currency = @currency
+		GROUP BY cz.Nation
+		ORDER BY Revenue DESC, cz.Nation`,
+		revenueArgs(currency, start, end)).Scan(&citizenships).Error
+	return citizenships, err
+}
+
+// SumRevenueByCurrency sums the revenue in every currency but currency, which the report cannot convert
+func (u *ReportRepository) SumRevenueByCurrency(currency string, start time.Time, end time.Time) ([]*dto.RevenueCurrency, error) {
+	var currencies []*dto.RevenueCurrency
+	err := u.orm.Raw(revenueNights+`SELECT h.Currency, `+revenueColumns+`
+		FROM nights n JOIN histories h ON h.Id = n.HistoryId
+		WHERE h.Currency <> @currency
+		GROUP BY h.Currency
+		ORDER BY h.Currency`,
+		revenueArgs(currency, start, end)).Scan(&currencies).Error
+	return currencies, err
+}
+
+// ListKpiDays counts for every night from start to end the active rooms of roomType not out of service and the
+// stay segments occupying them, so a stay in a room left out of the inventory is left out of the nights sold too. A
+// stay adds its room charge divided by its nights to each night, when it is in currency.
+func (u *ReportRepository) ListKpiDays(roomType string, currency string, start time.Time, end time.Time) ([]*dto.KpiDay, error) {
+	var days []*dto.KpiDay
+	err := u.orm.Raw(`WITH RECURSIVE days (Day) AS (
+			SELECT CAST(@start AS DATE)
+			UNION ALL
+			SELECT Day + INTERVAL 1 DAY FROM days WHERE Day + INTERVAL 1 DAY < @end
+		),
+		inventory AS (
+			SELECT d.Day, COUNT(r.Id) AS Available
+			FROM days d LEFT JOIN rooms r ON r.Active = TRUE AND (@type = '' OR r.Type = @type)
+				AND NOT EXISTS (SELECT 1 FROM room_outages o WHERE o.RoomId = r.Id AND o.StartDate <= d.Day AND o.EndDate >= d.Day)
+			GROUP BY d.Day
+		),
+		sold AS (
+			SELECT d.Day, COUNT(s.Id) AS Occupied,
+				COALESCE(SUM(CASE WHEN h.Currency = @currency THEN h.RoomCharge / NULLIF(DATEDIFF(h.CheckOut, h.CheckIn), 0) ELSE 0 END), 0) AS RoomRevenue
+			FROM days d
+			LEFT JOIN stay_segments s ON s.CheckIn <= d.Day AND s.CheckOut > d.Day
+				AND s.RoomId IN (SELECT r.Id FROM rooms r WHERE r.Active = TRUE AND (@type = '' OR r.Type = @type)
+					AND NOT EXISTS (SELECT 1 FROM room_outages o WHERE o.RoomId = r.Id AND o.StartDate <= d.Day AND o.EndDate >= d.Day))
+			LEFT JOIN histories h ON h.Id = s.HistoryId
+			GROUP BY d.Day
+		)
+		SELECT DATE_FORMAT(i.Day, '%Y-%m-%d') AS Date, i.Available, o.Occupied, CAST(ROUND(o.RoomRevenue) AS SIGNED) AS RoomRevenue
+		FROM inventory i JOIN sold o ON o.Day = i.Day
+		ORDER BY i.Day`,
+		map[string]interface{}{
+			"type":     roomType,
+			"currency": currency,
+			"start":    start,
+			"end":      end,
+		}).Scan(&days).Error
+	return days, err
+}
+
+// CountGuestsByCitizenship counts every guest of the stays between start and end once, with the nights of their
+// stays falling between start and end
+func (u *ReportRepository) CountGuestsByCitizenship(start time.Time, end time.Time) ([]*dto.NationalityRow, error) {
+	var rows []*dto.NationalityRow
+	err := u.orm.Raw(`SELECT n.Nation, n.Alpha3, COUNT(DISTINCT g.CustomerId) AS Guests,
+			COALESCE(SUM(DATEDIFF(LEAST(h.CheckOut, @end), GREATEST(h.CheckIn, @start))), 0) AS GuestNights
+		FROM stay_guests g
+			JOIN histories h ON h.Id = g.HistoryId
+			JOIN customers c ON c.Id = g.CustomerId
+			JOIN citizenships n ON n.Id = c.CitizenshipId
+		WHERE h.Kind = @stay AND h.CheckIn < @end AND h.CheckOut > @start
+		GROUP BY n.Id, n.Nation, n.Alpha3
+		ORDER BY Guests DESC, n.Nation`,
+		map[string]interface{}{
+			"stay":  model.HistoryKindStay,
+			"start": start,
+			"end":   end,
+		}).Scan(&rows).Error
+	return rows, err
+}
+
+// ListBookedDays counts for every night from start to end the Reservations occupying it. A reservation adds its price
+// divided by its nights to each night.
+func (u *ReportRepository) ListBookedDays(statuses []string, currency string, createdBefore time.Time, start time.Time, end time.Time) ([]*dto.ForecastFigures, error) {
+	var days []*dto.ForecastFigures
+	err := u.orm.Raw(`WITH RECURSIVE days (Day) AS (
+			SELECT CAST(@start AS DATE)
+			UNION ALL
+			SELECT Day + INTERVAL 1 DAY FROM days WHERE Day + INTERVAL 1 DAY < @end
+		)
+		SELECT COUNT(r.Id) AS Rooms,
+			CAST(ROUND(COALESCE(SUM(r.Price * @unit / NULLIF(DATEDIFF(r.CheckOut, r.CheckIn), 0)), 0)) AS SIGNED) AS Revenue
+		FROM days d
+		LEFT JOIN reservations r ON r.CheckIn <= d.Day AND r.CheckOut > d.Day AND r.Status IN @statuses
+			AND (@all OR r.CreatedAt < @before)
+		GROUP BY d.Day
+		ORDER BY d.Day`,
+		map[string]interface{}{
+			"statuses": statuses,
+			"unit":     model.MoneyFromMajor(1, currency).Amount,
+			"all":      createdBefore.IsZero(),
+			"before":   createdBefore,
+			"start":    start,
+			"end":      end,
+		}).Scan(&days).Error
+	return days, err
+}
+
+func (u *ReportRepository) SumCustomerValue(customerId uuid.UUID, currency string) (*dto.CustomerValue, error) {
+	value := &dto.CustomerValue{}
+	err := u.orm.Raw(`SELECT c.Id AS CustomerId, c.Name, `+customerValueColumns+`
+		FROM customers c LEFT JOIN histories h ON h.CustomerId = c.Id
+		WHERE c.Id = @customer
+		GROUP BY c.Id, c.Name`,
+		map[string]interface{}{
+			"stay":     model.HistoryKindStay,
+			"currency": currency,
+			"customer": customerId,
+		}).Scan(value).Error
+	return value, err
+}
+
+// ListTopGuests ranks the customers with a stay by orderBy, which must be a column of customerValueColumns
+func (u *ReportRepository) ListTopGuests(orderBy string, limit int, currency string, start time.Time, end time.Time) ([]*dto.CustomerValue, error) {
+	var guests []*dto.CustomerValue
+	err := u.orm.Raw(`SELECT c.Id AS CustomerId, c.Name, `+customerValueColumns+`
+		FROM histories h JOIN customers c ON c.Id = h.CustomerId
+		WHERE @all OR (h.CheckIn >= @start AND h.CheckIn < @end)
+		GROUP BY c.Id, c.Name
+		HAVING Stays > 0
+		ORDER BY `+orderBy+` DESC, c.Name
+		LIMIT @limit`,
+		map[string]interface{}{
+			"stay":     model.HistoryKindStay,
+			"currency": currency,
+			"all":      start.IsZero(),
+			"start":    start,
+			"end":      end,
+			"limit":    limit,
+		}).Scan(&guests).Error
+	return guests, err
+}
+
+func (u *ReportRepository) CountRepeatGuests(start time.Time, end time.Time) (*dto.RepeatRateFigures, error) {
+	figures := &dto.RepeatRateFigures{}
+	err := u.orm.Raw(repeatGuestsQuery("''"), repeatGuestsArgs("", start, end)).Scan(figures).Error
+	return figures, err
+}
+
+func (u *ReportRepository) CountRepeatGuestsByPeriod(format string, start time.Time, end time.Time) ([]*dto.RepeatRatePeriod, error) {
+	var periods []*dto.RepeatRatePeriod
+	err := u.orm.Raw(repeatGuestsQuery("DATE_FORMAT(h.CheckIn, @format)"), repeatGuestsArgs(format, start, end)).Scan(&periods).Error
+	return periods, err
+}
+
+// repeatGuestsQuery counts per period the customers with a stay checking in between start and end. A customer is a
+// repeat guest of a period when they stayed before it or stay twice in it.
+func repeatGuestsQuery(period string) string {
+	return `WITH firsts AS (
+			SELECT CustomerId, MIN(CheckIn) AS FirstStay FROM histories WHERE Kind = @stay GROUP BY CustomerId
+		),
+		visits AS (
+			SELECT ` + period + ` AS Period, h.CustomerId, COUNT(*) AS Stays, MIN(h.CheckIn) AS FirstInPeriod, MIN(f.FirstStay) AS FirstStay
+			FROM histories h JOIN firsts f ON f.CustomerId = h.CustomerId
+			WHERE h.Kind = @stay AND h.CheckIn >= @start AND h.CheckIn < @end
+			GROUP BY Period, h.CustomerId
+		)
+		SELECT Period, COUNT(*) AS Guests, COALESCE(SUM(CASE WHEN Stays >= 2 OR FirstStay < FirstInPeriod THEN 1 ELSE 0 END), 0) AS RepeatGuests
+		FROM visits
+		GROUP BY Period
+		ORDER BY Period`
+}
+
+func repeatGuestsArgs(format string, start time.Time, end time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"stay":   model.HistoryKindStay,
+		"format": format,
+		"start":  start,
+		"end":    end,
+	}
+}
+
+func revenueArgs(currency string, start time.Time, end time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"stay":     model.HistoryKindStay,
+		"currency": currency,
+		"start":    start,
+		"end":      end,
+	}
+}
@@ -0,0 +1,455 @@
+package service
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/config"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+// reportPeriod is a granularity of the reports: how its periods are labelled in Go and MySQL, where the period
+// holding a date begins, and how to step from one period to another
+type reportPeriod struct {
+	layout string
+	format string
+	begin  func(date time.Time) time.Time
+	add    func(date time.Time, n int) time.Time
+}
+
+var reportPeriods = map[string]reportPeriod{
+	"day": {
+		layout: "2006-01-02",
+		format: "%Y-%m-%d",
+		begin:  func(date time.Time) time.Time { return date },
+		add:    func(date time.Time, n int) time.Time { return date.AddDate(0, 0, n) },
+	},
+	"month": {
+		layout: "2006-01",
+		format: "%Y-%m",
+		begin:  func(date time.Time) time.Time { return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local) },
+		add:    func(date time.Time, n int) time.Time { return date.AddDate(0, n, 0) },
+	},
+	"year": {
+		layout: "2006",
+		format: "%Y",
+		begin:  func(date time.Time) time.Time { return time.Date(date.Year(), 1, 1, 0, 0, 0, 0, time.Local) },
+		add:    func(date time.Time, n int) time.Time { return date.AddDate(n, 0, 0) },
+	},
+}
+
+// topGuestOrders maps the rankings of the top guests to the columns they sort on
+var topGuestOrders = map[string]string{
+	"spend":  "Spend",
+	"stays":  "Stays",
+	"nights": "Nights",
+}
+
+const (
+	defaultTopGuests = 10
+	maxTopGuests     = 100
+)
+
+const (
+	defaultForecastDays = 90
+	// forecastPaceDays is how far back the forecast looks for last year, 52 weeks so that weekdays line up
+	forecastPaceDays = 364
+)
+
+// maxKpiNights bounds the KPI report, whose nights are generated one by one in SQL
+const maxKpiNights = 366
+
+type ReportService struct {
+	repo domain.ReportRepository
+}
+
+func NewReportService(repo domain.ReportRepository) domain.ReportService {
+	return &ReportService{
+		repo: repo,
+	}
+}
+
+// RevenueReport sums the nights of the Histories within the range, widened to whole periods, per period, room and
+// citizenship. Each period is compared with the one before it and the whole range with as many periods before it.
+// Histories in other currencies are summed apart, per currency.
+func (u *ReportService) RevenueReport(in *dto.RevenueReportRequest) (*dto.RevenueReport, error) {
+	var err error
+	var start, end time.Time
+	var currency string
+	var previous *dto.RevenueFigures
+	var rows []*dto.RevenuePeriod
+	period, ok := reportPeriods[in.Period]
+	if !ok {
+		return nil, errors.New("error CRMS : Period must be day, month or year")
+	}
+	if start, end, err = parseRange(in.StartDate, in.EndDate); err != nil {
+		return nil, err
+	}
+	if currency, err = reportCurrency(in.Currency); err != nil {
+		return nil, err
+	}
+	start = period.begin(start)
+	count := 0
+	for date := start; date.Before(end); date = period.add(date, 1) {
+		count++
+	}
+	end = period.add(start, count)
+
+	report := &dto.RevenueReport{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Period:    in.Period,
+		Currency:  currency,
+	}
+	if rows, err = u.repo.SumRevenueByPeriod(period.format, currency, period.add(start, -1), end); err != nil {
+		return nil, err
+	}
+	figures := make(map[string]dto.RevenueFigures)
+	for _, row := range rows {
+		figures[row.Period] = row.RevenueFigures
+	}
+	for date := start; date.Before(end); date = period.add(date, 1) {
+		row := &dto.RevenuePeriod{
+			Period:         date.Format(period.layout),
+			RevenueFigures: figures[date.Format(period.layout)],
+			Previous:       figures[period.add(date, -1).Format(period.layout)],
+		}
+		row.RevenueChange = percentChange(row.Revenue, row.Previous.Revenue)
+		report.Periods = append(report.Periods, row)
+		report.Total.Revenue += row.Revenue
+		report.Total.NetRevenue += row.NetRevenue
+		report.Total.Stays += row.Stays
+		report.Total.Guests += row.Guests
+	}
+	if previous, err = u.repo.SumRevenue(currency, period.add(start, -count), start); err != nil {
+		return nil, err
+	}
+	report.Previous = *previous
+	report.RevenueChange = percentChange(report.Total.Revenue, report.Previous.Revenue)
+	if report.ByRoom, err = u.repo.SumRevenueByRoom(currency, start, end); err != nil {
+		return nil, err
+	}
+	if report.ByCitizenship, err = u.repo.SumRevenueByCitizenship(currency, start, end); err != nil {
+		return nil, err
+	}
+	if report.ByCurrency, err = u.repo.SumRevenueByCurrency(currency, start, end); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// KpiReport computes the occupancy, ADR and RevPAR of every night of the range and of the range as a whole
+func (u *ReportService) KpiReport(in *dto.KpiReportRequest) (*dto.KpiReport, error) {
+	var err error
+	var start, end time.Time
+	var currency string
+	var days []*dto.KpiDay
+	if start, end, err = parseRange(in.StartDate, in.EndDate); err != nil {
+		return nil, err
+	}
+	if end.After(start.AddDate(0, 0, maxKpiNights)) {
+		return nil, errors.New("error CRMS : Date range is too long")
+	}
+	if currency, err = reportCurrency(in.Currency); err != nil {
+		return nil, err
+	}
+	if days, err = u.repo.ListKpiDays(in.RoomType, currency, start, end); err != nil {
+		return nil, err
+	}
+
+	report := &dto.KpiReport{
+		StartDate: in.StartDate,
+		EndDate:   in.EndDate,
+		RoomType:  in.RoomType,
+		Currency:  currency,
+		Days:      days,
+	}
+	for _, day := range days {
+		computeKpis(&day.KpiFigures)
+		report.Total.Available += day.Available
+		report.Total.Occupied += day.Occupied
+		report.Total.RoomRevenue += day.RoomRevenue
+	}
+	computeKpis(&report.Total)
+	return report, nil
+}
+
+// NationalityReport counts the guests staying a night of the range and their nights in it per citizenship, as
+// reported to the tourism bureau
+func (u *ReportService) NationalityReport(in *dto.NationalityReportRequest) (*dto.NationalityReport, error) {
+	var err error
+	var start, end time.Time
+	var rows []*dto.NationalityRow
+	if start, end, err = parseRange(in.StartDate, in.EndDate); err != nil {
+		return nil, err
+	}
+	if rows, err = u.repo.CountGuestsByCitizenship(start, end); err != nil {
+		return nil, err
+	}
+
+	report := &dto.NationalityReport{
+		StartDate: in.StartDate,
+		EndDate:   in.EndDate,
+		Rows:      rows,
+	}
+	for _, row := range rows {
+		report.Total.Guests += row.Guests
+		report.Total.GuestNights += row.GuestNights
+	}
+	return report, nil
+}
+
+func (u *ReportService) CustomerValue(in *dto.CustomerValueRequest) (*dto.CustomerValue, error) {
+	var err error
+	var currency string
+	var value *dto.CustomerValue
+	if currency, err = reportCurrency(in.Currency); err != nil {
+		return nil, err
+	}
+	if value, err = u.repo.SumCustomerValue(in.CustomerId, currency); err != nil {
+		return nil, err
+	}
+	if value.CustomerId == uuid.Nil {
+		return nil, errors.New("error CRMS : There is no this customer")
+	}
+	value.Currency = currency
+	computeAverageSpend(value)
+	return value, nil
+}
+
+// TopGuests ranks the customers over their stays checking in within the range, or over every stay without a range
+func (u *ReportService) TopGuests(in *dto.TopGuestsRequest) (*dto.TopGuests, error) {
+	var err error
+	var start, end time.Time
+	var currency string
+	if in.OrderBy == "" {
+		in.OrderBy = "spend"
+	}
+	orderBy, ok := topGuestOrders[in.OrderBy]
+	if !ok {
+		return nil, errors.New("error CRMS : Order must be spend, stays or nights")
+	}
+	if in.Limit == 0 {
+		in.Limit = defaultTopGuests
+	}
+	if in.Limit < 0 || in.Limit > maxTopGuests {
+		return nil, errors.New("error CRMS : Limit must be between 1 and 100")
+	}
+	if in.StartDate != "" || in.EndDate != "" {
+		if start, end, err = parseRange(in.StartDate, in.EndDate); err != nil {
+			return nil, err
+		}
+	}
+	if currency, err = reportCurrency(in.Currency); err != nil {
+		return nil, err
+	}
+
+	guests := &dto.TopGuests{
+		StartDate: in.StartDate,
+		EndDate:   in.EndDate,
+		OrderBy:   in.OrderBy,
+		Currency:  currency,
+	}
+	if guests.Guests, err = u.repo.ListTopGuests(orderBy, in.Limit, currency, start, end); err != nil {
+		return nil, err
+	}
+	for _, guest := range guests.Guests {
+		guest.Currency = currency
+		computeAverageSpend(guest)
+	}
+	return guests, nil
+}
+
+// RepeatRateReport counts per period, widened to whole periods like the revenue report, the guests checking in and
+// those of them staying a second time, whether the first stay was in an earlier period or in the same one
+func (u *ReportService) RepeatRateReport(in *dto.RepeatRateRequest) (*dto.RepeatRateReport, error) {
+	var err error
+	var start, end time.Time
+	var total *dto.RepeatRateFigures
+	var rows []*dto.RepeatRatePeriod
+	period, ok := reportPeriods[in.Period]
+	if !ok {
+		return nil, errors.New("error CRMS : Period must be day, month or year")
+	}
+	if start, end, err = parseRange(in.StartDate, in.EndDate); err != nil {
+		return nil, err
+	}
+	start = period.begin(start)
+	count := 0
+	for date := start; date.Before(end); date = period.add(date, 1) {
+		count++
+	}
+	end = period.add(start, count)
+
+	report := &dto.RepeatRateReport{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Period:    in.Period,
+	}
+	if rows, err = u.repo.CountRepeatGuestsByPeriod(period.format, start, end); err != nil {
+		return nil, err
+	}
+	figures := make(map[string]dto.RepeatRateFigures)
+	for _, row := range rows {
+		figures[row.Period] = row.RepeatRateFigures
+	}
+	for date := start; date.Before(end); date = period.add(date, 1) {
+		row := &dto.RepeatRatePeriod{
+			Period:            date.Format(period.layout),
+			RepeatRateFigures: figures[date.Format(period.layout)],
+		}
+		row.RepeatRate = percentOf(row.RepeatGuests, row.Guests)
+		report.Periods = append(report.Periods, row)
+	}
+	if total, err = u.repo.CountRepeatGuests(start, end); err != nil {
+		return nil, err
+	}
+	report.Total = *total
+	report.Total.RepeatRate = percentOf(report.Total.RepeatGuests, report.Total.Guests)
+	return report, nil
+}
+
+// Forecast projects the coming nights from what is on the books, the stays in house and the confirmed reservations,
+// and the pace of last year: the rooms of the same night 52 weeks ago that were booked after the same lead time,
+// whether as reservations or walk-ins, are added as pickup without exceeding the rooms available.
+func (u *ReportService) Forecast(in *dto.ForecastRequest) (*dto.Forecast, error) {
+	var err error
+	var inHouse, lastYear []*dto.KpiDay
+	var booked, lastYearBooked []*dto.ForecastFigures
+	if in.Days == 0 {
+		in.Days = defaultForecastDays
+	}
+	if in.Days < 0 || in.Days > maxKpiNights {
+		return nil, errors.New("error CRMS : Days must be between 1 and 366")
+	}
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, in.Days)
+	currency := config.Currency()
+	if inHouse, err = u.repo.ListKpiDays("", currency, start, end); err != nil {
+		return nil, err
+	}
+	if booked, err = u.repo.ListBookedDays([]string{model.ReservationConfirmed}, currency, time.Time{}, start, end); err != nil {
+		return nil, err
+	}
+	if lastYear, err = u.repo.ListKpiDays("", currency, start.AddDate(0, 0, -forecastPaceDays), end.AddDate(0, 0, -forecastPaceDays)); err != nil {
+		return nil, err
+	}
+	statuses := []string{model.ReservationCheckedIn, model.ReservationCheckedOut}
+	if lastYearBooked, err = u.repo.ListBookedDays(statuses, currency, now.AddDate(0, 0, -forecastPaceDays), start.AddDate(0, 0, -forecastPaceDays), end.AddDate(0, 0, -forecastPaceDays)); err != nil {
+		return nil, err
+	}
+	if len(booked) != len(inHouse) || len(lastYear) != len(inHouse) || len(lastYearBooked) != len(inHouse) {
+		return nil, errors.New("error CRMS : Forecast nights do not match")
+	}
+
+	forecast := &dto.Forecast{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Currency:  currency,
+	}
+	for i, night := range inHouse {
+		day := &dto.ForecastDay{
+			Date:      night.Date,
+			Available: night.Available,
+			OnTheBooks: dto.ForecastFigures{
+				Rooms:   night.Occupied + booked[i].Rooms,
+				Revenue: night.RoomRevenue + booked[i].Revenue,
+			},
+		}
+		pickup := lastYear[i].Occupied - lastYearBooked[i].Rooms
+		if rooms := day.Available - day.OnTheBooks.Rooms; pickup > rooms {
+			pickup = rooms
+		}
+		if pickup > 0 {
+			day.Pickup.Rooms = pickup
+			day.Pickup.Revenue = divideRounded((lastYear[i].RoomRevenue-lastYearBooked[i].Revenue)*int64(pickup), int64(lastYear[i].Occupied-lastYearBooked[i].Rooms))
+			if day.Pickup.Revenue < 0 {
+				day.Pickup.Revenue = 0
+			}
+		}
+		day.Projected.Rooms = day.OnTheBooks.Rooms + day.Pickup.Rooms
+		day.Projected.Revenue = day.OnTheBooks.Revenue + day.Pickup.Revenue
+		computeOccupancies(day)
+		forecast.Days = append(forecast.Days, day)
+		forecast.Total.Available += day.Available
+		forecast.Total.OnTheBooks.Rooms += day.OnTheBooks.Rooms
+		forecast.Total.OnTheBooks.Revenue += day.OnTheBooks.Revenue
+		forecast.Total.Pickup.Rooms += day.Pickup.Rooms
+		forecast.Total.Pickup.Revenue += day.Pickup.Revenue
+		forecast.Total.Projected.Rooms += day.Projected.Rooms
+		forecast.Total.Projected.Revenue += day.Projected.Revenue
+	}
+	computeOccupancies(&forecast.Total)
+	return forecast, nil
+}
+
+func computeOccupancies(day *dto.ForecastDay) {
+	day.OnTheBooks.Occupancy = percentOf(day.OnTheBooks.Rooms, day.Available)
+	day.Pickup.Occupancy = percentOf(day.Pickup.Rooms, day.Available)
+	day.Projected.Occupancy = percentOf(day.Projected.Rooms, day.Available)
+}
+
+func computeAverageSpend(value *dto.CustomerValue) {
+	if value.Stays != 0 {
+		value.AverageSpend = divideRounded(value.Spend, int64(value.Stays))
+	}
+}
+
+// computeKpis derives the ratios from the room-nights and the room revenue, leaving them 0 when there is nothing
+// to divide by
+func computeKpis(figures *dto.KpiFigures) {
+	if figures.Available != 0 {
+		figures.Occupancy = math.Round(float64(figures.Occupied)*10000/float64(figures.Available)) / 100
+		figures.RevPAR = divideRounded(figures.RoomRevenue, int64(figures.Available))
+	}
+	if figures.Occupied != 0 {
+		figures.ADR = divideRounded(figures.RoomRevenue, int64(figures.Occupied))
+	}
+}
+
+func divideRounded(amount int64, count int64) int64 {
+	return int64(math.Round(float64(amount) / float64(count)))
+}
+
+// parseRange parses the dates of a report into the start of the first day and the end of the last one
+func parseRange(startDate string, endDate string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02", startDate, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("error CRMS : Date is incomplete")
+	}
+	end, err := time.ParseInLocation("2006-01-02", endDate, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("error CRMS : Date is incomplete")
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, errors.New("error CRMS : Start date is after end date")
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+func reportCurrency(currency string) (string, error) {
+	if currency == "" {
+		return config.Currency(), nil
+	}
+	return model.NormalizeCurrency(currency)
+}
+
+// percentOf returns part as a percent of whole, rounded to two decimals
+func percentOf(part int, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*10000/float64(whole)) / 100
+}
+
+// percentChange returns the change from previous to current in percent, rounded to two decimals
+func percentChange(current int64, previous int64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return math.Round(float64(current-previous)*10000/float64(previous)) / 100
+}
@@ -0,0 +1,365 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ReservationHandler struct {
+	ser domain.ReservationService
+}
+
+func NewReservationHandler(e *gin.Engine, ser domain.ReservationService) {
+	handler := &ReservationHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/reservationList", handler.ListReservations)
+		api.POST("/reservationId", handler.GetReservationByReservationId)
+		api.POST("/reservationCustomerId", handler.GetReservationsByCustomerId)
+		api.POST("/reservationForDuring", handler.GetReservationsForDuring)
+		api.POST("/reservationCre", handler.CreateReservation)
+		api.POST("/reservationMod", handler.ModifyReservation)
+		api.POST("/reservationStatus", handler.ChangeReservationStatus)
+		api.POST("/reservationCheckIn", handler.CheckInReservation)
+		api.POST("/reservationCheckOut", handler.CheckOutReservation)
+	}
+}
+
+// ListReservations @Summary ListReservations
+// @Description Get all Reservations
+// @Tags Reservation
+// @Produce application/json
+// @Success 200 {object} []model.Reservation
+// @Failure 500 {string} string "{"Message": "Internal Error!"}"
+// @Router /reservationList [post]
+func (u *ReservationHandler) ListReservations(c *gin.Context) {
+	reservationList, err := u.ser.ListReservations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": "Internal Error!",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":      "List all reservations",
+		"reservations": reservationList,
+	})
+}
+
+// GetReservationByReservationId @Summary GetReservationByReservationId
+// @Description Get Reservation by ReservationId
+// @Tags Reservation
+// @Produce application/json
+// @Param ReservationId body dto.ReservationIdRequest true "Reservation id"
+// @Success 200 {object} model.Reservation
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reservationId [post]
+func (u *ReservationHandler) GetReservationByReservationId(c *gin.Context) {
+	request := dto.ReservationIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	reservationData, err := u.ser.GetReservationByReservationId(request.ReservationId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this reservation" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, reservationData)
+}
+
+// GetReservationsByCustomerId @Summary GetReservationsByCustomerId
+// @Description Get Reservations by CustomerId
+// @Tags Reservation
+// @Produce application/json
+// @Param CustomerId body dto.ReservationCustomerIdRequest true "Customer id"
+// @Success 200 {object} []model.Reservation
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reservationCustomerId [post]
+func (u *ReservationHandler) GetReservationsByCustomerId(c *gin.Context) {
+	request := dto.ReservationCustomerIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	reservationList, err := u.ser.ListReservationsByCustomerId(request.CustomerId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this customer" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":      "List reservations of the customer",
+		"reservations": reservationList,
+	})
+}
+
+// GetReservationsForDuring @Summary GetReservationsForDuring
+// @Description Get Reservations whose stay overlaps the period
+// @Tags Reservation
+// @Produce application/json
+// @Param During body dto.DuringRequest true "Period" example: {"startDate": "2020-01-01", "endDate": "2020-01-31"}
+// @Success 200 {object} []model.Reservation
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reservationForDuring [post]
+func (u *ReservationHandler) GetReservationsForDuring(c *gin.Context) {
+	request := dto.DuringRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	reservationList, err := u.ser.ListReservationsForDuring(request.StartDate, request.EndDate)
+	if err != nil {
+		if err.Error() == "error CRMS : Date is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Start date is after end date" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":      "List reservations for during",
+		"reservations": reservationList,
+	})
+}
+
+// CreateReservation @Summary CreateReservation
+// @Description Create a new Reservation, tentative unless Status is confirmed
+// @Tags Reservation
+// @Accept json
+// @Produce application/json
+// @Param Reservation body dto.ReservationRequest true "Reservation Information" example: {"CustomerId": "00000000-0000-0000-0000-000000000000", "RoomId": "00000000-0000-0000-0000-000000000000", "CheckIn": "2020-01-01", "CheckOut": "2020-01-03", "NumberOfPeople": 2, "Price": 4800, "Status": "confirmed"}
+// @Success 200 {object} model.Reservation
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reservationCre [post]
+func (u *ReservationHandler) CreateReservation(c *gin.Context) {
+	request := dto.ReservationRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	createReservation, err := transformToReservation(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	createReservation, err = u.ser.CreateReservation(createReservation)
+	if err != nil {
+		u.respondReservationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, createReservation)
+}
+
+// ModifyReservation @Summary ModifyReservation
+// @Description Modify a tentative or confirmed Reservation
+// @Tags Reservation
+// @Accept json
+// @Produce application/json
+// @Param Reservation body dto.ReservationRequest true "Reservation Information"
+// @Success 200 {object} model.Reservation
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reservationMod [post]
+func (u *ReservationHandler) ModifyReservation(c *gin.Context) {
+	request := dto.ReservationRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	modifyReservation, err := transformToReservation(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	modifyReservation, err = u.ser.UpdateReservation(modifyReservation)
+	if err != nil {
+		u.respondReservationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, modifyReservation)
+}
+
+// @Description Confirm, cancel or mark a Reservation as no-show. A fee due under its cancellation policy is posted as a History
+// @Description Confirm, cancel or mark a Reservation as no-show
+// @Tags Reservation
+// @Accept json
+// @Produce application/json
+// @Param Status body dto.ReservationStatusRequest true "Reservation status" example: {"ReservationId": "00000000-0000-0000-0000-000000000000", "Status": "confirmed"}
+// @Success 200 {object} model.Reservation
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reservationStatus [post]
+func (u *ReservationHandler) ChangeReservationStatus(c *gin.Context) {
+	request := dto.ReservationStatusRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	reservation, err := u.ser.ChangeReservationStatus(request.ReservationId, request.Status)
+	if err != nil {
+		u.respondReservationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reservation)
+}
+
+// CheckInReservation @Summary CheckInReservation
+// @Description Check in a confirmed Reservation and create its History
+// @Tags Reservation
+// @Produce application/json
+// @Param ReservationId body dto.ReservationIdRequest true "Reservation id"
+// @Success 200 {object} model.Reservation
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reservationCheckIn [post]
+func (u *ReservationHandler) CheckInReservation(c *gin.Context) {
+	request := dto.ReservationIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	reservation, err := u.ser.CheckInReservation(request.ReservationId)
+	if err != nil {
+		u.respondReservationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reservation)
+}
+
+// CheckOutReservation @Summary CheckOutReservation
+// @Description Check out a checked-in Reservation, shortening its History on an early departure
+// @Tags Reservation
+// @Produce application/json
+// @Param ReservationId body dto.ReservationIdRequest true "Reservation id"
+// @Success 200 {object} model.Reservation
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /reservationCheckOut [post]
+func (u *ReservationHandler) CheckOutReservation(c *gin.Context) {
+	request := dto.ReservationIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	reservation, err := u.ser.CheckOutReservation(request.ReservationId)
+	if err != nil {
+		u.respondReservationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reservation)
+}
+
+// respondReservationError answers the business errors shared by the reservation write endpoints
+func (u *ReservationHandler) respondReservationError(c *gin.Context, err error) {
+	switch {
+	case strings.HasPrefix(err.Error(), "error CRMS : Room is already booked"),
+		err.Error() == "error CRMS : There is no this reservation",
+		err.Error() == "error CRMS : There is no this customer",
+		err.Error() == "error CRMS : There is no this room",
+		err.Error() == "error CRMS : There is no this history",
+		err.Error() == "error CRMS : This room is not active",
+		err.Error() == "error CRMS : Number of people exceeds room capacity",
+		err.Error() == "error CRMS : Reservation Info is incomplete",
+		err.Error() == "error CRMS : Reservation status is invalid",
+		err.Error() == "error CRMS : Check-out date must be after check-in date",
+		err.Error() == "error CRMS : Check-in date is before today",
+		err.Error() == "error CRMS : Only tentative or confirmed reservations can be modified",
+		err.Error() == "error CRMS : Use check-in or check-out to change this status",
+		err.Error() == "error CRMS : It is too early to check in",
+		err.Error() == "error CRMS : The reservation has already ended",
+		err.Error() == "error CRMS : It is too early to mark a no-show",
+		err.Error() == "error CRMS : There is no this cancellation policy",
+		err.Error() == "error CRMS : This cancellation policy is not active",
+		err.Error() == "error CRMS : There is no this booking group",
+		err.Error() == "error CRMS : There is no rate plan for this room type",
+		strings.HasPrefix(err.Error(), "error CRMS : Reservation cannot change from"):
+		c.JSON(http.StatusOK, gin.H{
+			"Message": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+	}
+}
+
+func transformToReservation(requestData dto.ReservationRequest) (*model.Reservation, error) {
+	checkIn, err := time.ParseInLocation("2006-01-02", requestData.CheckIn, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	checkOut, err := time.ParseInLocation("2006-01-02", requestData.CheckOut, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	reservation := &model.Reservation{
+		Id:             requestData.ReservationId,
+		CustomerId:     requestData.CustomerId,
+		RoomId:         requestData.RoomId,
+		CheckIn:        checkIn,
+		CheckOut:       checkOut,
+		NumberOfPeople: requestData.NumberOfPeople,
+		Price:          requestData.Price,
+		Status:         requestData.Status,
+		Note:           requestData.Note,
+	}
+	if requestData.PolicyId != uuid.Nil {
+		reservation.CancellationPolicyId = &requestData.PolicyId
+	}
+	if requestData.GroupId != uuid.Nil {
+		reservation.GroupId = &requestData.GroupId
+	}
+	return reservation, nil
+}
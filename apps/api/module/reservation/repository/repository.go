@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/module/internal/stay"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReservationRepository struct {
+	orm *gorm.DB
+}
+
+func NewReservationRepository(orm *gorm.DB) domain.ReservationRepository {
+	return &ReservationRepository{
+		orm: orm,
+	}
+}
+
+func (u *ReservationRepository) ListReservations() ([]*model.Reservation, error) {
+	var reservations []*model.Reservation
+	err := u.orm.Preload("Room").Order("CheckIn").Find(&reservations).Error
+	return reservations, err
+}
+
+func (u *ReservationRepository) ListReservationsByCustomer(reservation *model.Reservation) ([]*model.Reservation, error) {
+	var reservations []*model.Reservation
+	err := u.orm.Preload("Room").Where("CustomerId = ?", reservation.CustomerId).Order("CheckIn").Find(&reservations).Error
+	return reservations, err
+}
+
+func (u *ReservationRepository) ListReservationsForDuring(reservation1 *model.Reservation, reservation2 *model.Reservation) ([]*model.Reservation, error) {
+	var reservations []*model.Reservation
+	err := u.orm.Preload("Room").Where("CheckIn < ? AND CheckOut > ?", reservation2.CheckIn, reservation1.CheckIn).Order("CheckIn").Find(&reservations).Error
+	return reservations, err
+}
+
+func (u *ReservationRepository) GetReservationByReservationId(reservation *model.Reservation) (*model.Reservation, error) {
+	err := u.orm.Preload("Room").Where("Id = ?", reservation.Id).Find(&reservation).Error
+	return reservation, err
+}
+
+func (u *ReservationRepository) CreateReservation(reservation *model.Reservation) (*model.Reservation, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := stay.CheckRoomAvailability(tx, reservation.RoomId, reservation.CheckIn, reservation.CheckOut, reservation.Id); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(&reservation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetReservationByReservationId(reservation)
+}
+
+func (u *ReservationRepository) UpdateReservation(reservation *model.Reservation) (*model.Reservation, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := stay.CheckRoomAvailability(tx, reservation.RoomId, reservation.CheckIn, reservation.CheckOut, reservation.Id); err != nil {
+			return err
+		}
+		return tx.Model(reservation).Select("CustomerId", "RoomId", "CheckIn", "CheckOut", "NumberOfPeople", "Price", "CancellationPolicyId", "GroupId", "Note").Where("Id = ?", reservation.Id).Updates(&reservation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetReservationByReservationId(reservation)
+}
+
+func (u *ReservationRepository) UpdateReservationStatus(reservation *model.Reservation) (*model.Reservation, error) {
+	err := u.orm.Model(reservation).Where("Id = ?", reservation.Id).Update("Status", reservation.Status).Error
+	if err != nil {
+		return nil, err
+	}
+	return u.GetReservationByReservationId(reservation)
+}
+
+func (u *ReservationRepository) CheckInReservation(reservation *model.Reservation, history *model.History) (*model.Reservation, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(history).Error; err != nil {
+			return err
+		}
+		if err := stay.ReplaceStaySegments(tx, history, reservation.Id); err != nil {
+			return err
+		}
+		if err := stay.ReplacePrimaryGuest(tx, history.Id, history.CustomerId); err != nil {
+			return err
+		}
+		if len(history.TaxLines) != 0 {
+			if err := tx.Create(history.TaxLines).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&model.Payment{}).Where("ReservationId = ?", reservation.Id).Update("HistoryId", history.Id).Error; err != nil {
+			return err
+		}
+		return tx.Model(reservation).Where("Id = ?", reservation.Id).Updates(map[string]interface{}{
+			"Status":    reservation.Status,
+			"HistoryId": history.Id,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetReservationByReservationId(reservation)
+}
+
+func (u *ReservationRepository) GetReservationHistory(history *model.History) (*model.History, error) {
+	err := u.orm.Preload("TaxLines").Where("Id = ?", history.Id).Find(&history).Error
+	return history, err
+}
+
+// CheckOutReservation saves the check-out of a Reservation and its History, with the charges of the History after an
+// early departure
+func (u *ReservationRepository) CheckOutReservation(reservation *model.Reservation, history *model.History) (*model.Reservation, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.History{}).Where("Id = ?", history.Id).Updates(map[string]interface{}{
+			"CheckOut":    history.CheckOut,
+			"RoomCharge":  history.RoomCharge,
+			"Discount":    history.Discount,
+			"TaxAmount":   history.TaxAmount,
+			"TotalAmount": history.TotalAmount,
+			"Price":       history.Price,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId = ?", history.Id).Delete(&model.HistoryTaxLine{}).Error; err != nil {
+			return err
+		}
+		if len(history.TaxLines) != 0 {
+			if err := tx.Create(history.TaxLines).Error; err != nil {
+				return err
+			}
+		}
+		if err := stay.TruncateStaySegments(tx, history.Id, history.CheckOut); err != nil {
+			return err
+		}
+		return tx.Model(reservation).Where("Id = ?", reservation.Id).Updates(map[string]interface{}{
+			"Status":   reservation.Status,
+			"CheckOut": reservation.CheckOut,
+			"Price":    reservation.Price,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetReservationByReservationId(reservation)
+}
+
+// CancelReservation closes a cancelled or no-show reservation. A fee, when charged, is posted as a History that
+// takes over the deposits of the reservation.
+func (u *ReservationRepository) CancelReservation(reservation *model.Reservation, fee *model.History) (*model.Reservation, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"Status": reservation.Status,
+		}
+		if fee != nil {
+			if err := tx.Omit(clause.Associations).Create(fee).Error; err != nil {
+				return err
+			}
+			if len(fee.TaxLines) != 0 {
+				if err := tx.Create(fee.TaxLines).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Model(&model.Payment{}).Where("ReservationId = ?", reservation.Id).Update("HistoryId", fee.Id).Error; err != nil {
+				return err
+			}
+			updates["HistoryId"] = fee.Id
+		}
+		return tx.Model(reservation).Where("Id = ?", reservation.Id).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetReservationByReservationId(reservation)
+}
+
+func (u *ReservationRepository) GetCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) {
+	var err error
+	if policy.Id != uuid.Nil {
+		err = u.orm.Where("Id = ?", policy.Id).Find(&policy).Error
+	} else {
+		err = u.orm.Where("IsDefault = ? AND Active = ?", true, true).Find(&policy).Error
+	}
+	return policy, err
+}
+
+func (u *ReservationRepository) ConfirmGroupExistence(group *model.BookingGroup) (*model.BookingGroup, error) {
+	err := u.orm.Where("Id = ?", group.Id).Find(&group).Error
+	return group, err
+}
+
+func (u *ReservationRepository) ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error) {
+	err := u.orm.Where("Id = ?", customer.Id).Find(&customer).Error
+	return customer, err
+}
+
+func (u *ReservationRepository) ConfirmRoomExistence(room *model.Room) (*model.Room, error) {
+	err := u.orm.Where("Id = ?", room.Id).Find(&room).Error
+	return room, err
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/S1nceU/CRMS/apps/api/config"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"math"
+	"strconv"
+	"time"
+)
+
+// transitions lists the statuses a reservation may move to from each status
+var transitions = map[string][]string{
+	model.ReservationTentative: {model.ReservationConfirmed, model.ReservationCancelled},
+	model.ReservationConfirmed: {model.ReservationCheckedIn, model.ReservationCancelled, model.ReservationNoShow},
+	model.ReservationCheckedIn: {model.ReservationCheckedOut},
+}
+
+type ReservationService struct {
+	repo        domain.ReservationRepository
+	ratePlanSer domain.RatePlanService
+}
+
+func NewReservationService(repo domain.ReservationRepository, ratePlanSer domain.RatePlanService) domain.ReservationService {
+	return &ReservationService{
+		repo:        repo,
+		ratePlanSer: ratePlanSer,
+	}
+}
+
+func (u *ReservationService) ListReservations() ([]*model.Reservation, error) {
+	var err error
+	var point []*model.Reservation
+	if point, err = u.repo.ListReservations(); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfReservation(point), err
+}
+
+func (u *ReservationService) ListReservationsByCustomerId(in uuid.UUID) ([]*model.Reservation, error) {
+	var err error
+	var point []*model.Reservation
+	if _, err = u.confirmCustomer(in); err != nil {
+		return nil, err
+	}
+	newReservation := &model.Reservation{
+		CustomerId: in,
+	}
+	if point, err = u.repo.ListReservationsByCustomer(newReservation); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfReservation(point), err
+}
+
+func (u *ReservationService) ListReservationsForDuring(in1 string, in2 string) ([]*model.Reservation, error) {
+	var err error
+	var point []*model.Reservation
+	var date1 time.Time
+	var date2 time.Time
+
+	if date1, err = time.ParseInLocation("2006-01-02", in1, time.Local); err != nil {
+		return nil, errors.New("error CRMS : Date is incomplete")
+	}
+	if date2, err = time.ParseInLocation("2006-01-02", in2, time.Local); err != nil {
+		return nil, errors.New("error CRMS : Date is incomplete")
+	}
+	if date1.After(date2) {
+		return nil, errors.New("error CRMS : Start date is after end date")
+	}
+
+	newReservation1 := &model.Reservation{
+		CheckIn: date1,
+	}
+	newReservation2 := &model.Reservation{
+		CheckIn: date2.AddDate(0, 0, 1),
+	}
+	if point, err = u.repo.ListReservationsForDuring(newReservation1, newReservation2); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfReservation(point), err
+}
+
+func (u *ReservationService) GetReservationByReservationId(in uuid.UUID) (*model.Reservation, error) {
+	var err error
+	newReservation := &model.Reservation{
+		Id: in,
+	}
+	if newReservation, err = u.repo.GetReservationByReservationId(newReservation); err != nil {
+		return nil, err
+	} else if newReservation.CustomerId == uuid.Nil {
+		return nil, errors.New("error CRMS : There is no this reservation")
+	}
+	return newReservation, err
+}
+
+func (u *ReservationService) CreateReservation(in *model.Reservation) (*model.Reservation, error) {
+	var err error
+	if _, err = u.confirmCustomer(in.CustomerId); err != nil {
+		return nil, err
+	}
+	if err = validateReservationInfo(in); err != nil {
+		return nil, err
+	}
+	if in.CheckIn.Before(today()) {
+		return nil, errors.New("error CRMS : Check-in date is before today")
+	}
+	if err = u.confirmRoom(in); err != nil {
+		return nil, err
+	}
+	if err = u.confirmCancellationPolicy(in, nil); err != nil {
+		return nil, err
+	}
+	if err = u.confirmGroup(in.GroupId); err != nil {
+		return nil, err
+	}
+	if in.Status == "" {
+		in.Status = model.ReservationTentative
+	} else if in.Status != model.ReservationTentative && in.Status != model.ReservationConfirmed {
+		return nil, errors.New("error CRMS : Reservation status is invalid")
+	}
+	in.Id = uuid.New()
+	in.HistoryId = nil
+	return u.repo.CreateReservation(in)
+}
+
+func (u *ReservationService) UpdateReservation(in *model.Reservation) (*model.Reservation, error) {
+	var err error
+	var reservation *model.Reservation
+	if reservation, err = u.GetReservationByReservationId(in.Id); err != nil {
+		return nil, err
+	}
+	if reservation.Status != model.ReservationTentative && reservation.Status != model.ReservationConfirmed {
+		return nil, errors.New("error CRMS : Only tentative or confirmed reservations can be modified")
+	}
+	if _, err = u.confirmCustomer(in.CustomerId); err != nil {
+		return nil, err
+	}
+	if err = validateReservationInfo(in); err != nil {
+		return nil, err
+	}
+	if err = u.confirmRoom(in); err != nil {
+		return nil, err
+	}
+	if in.CancellationPolicyId == nil {
+		in.CancellationPolicyId = reservation.CancellationPolicyId
+	} else if err = u.confirmCancellationPolicy(in, reservation.CancellationPolicyId); err != nil {
+		return nil, err
+	}
+	if in.GroupId == nil {
+		in.GroupId = reservation.GroupId
+	} else if err = u.confirmGroup(in.GroupId); err != nil {
+		return nil, err
+	}
+	in.Status = reservation.Status
+	in.HistoryId = nil
+	return u.repo.UpdateReservation(in)
+}
+
+// ChangeReservationStatus confirms, cancels or marks a reservation as no-show. Checking in and out create and
+// complete a history, so they go through CheckInReservation and CheckOutReservation instead.
+func (u *ReservationService) ChangeReservationStatus(in uuid.UUID, status string) (*model.Reservation, error) {
+	var err error
+	var reservation *model.Reservation
+	var fee *model.History
+	if status == model.ReservationCheckedIn || status == model.ReservationCheckedOut {
+		return nil, errors.New("error CRMS : Use check-in or check-out to change this status")
+	}
+	if reservation, err = u.GetReservationByReservationId(in); err != nil {
+		return nil, err
+	}
+	if err = validateTransition(reservation.Status, status); err != nil {
+		return nil, err
+	}
+	if status == model.ReservationNoShow && today().Before(reservation.CheckIn) {
+		return nil, errors.New("error CRMS : It is too early to mark a no-show")
+	}
+	if status != model.ReservationCancelled && status != model.ReservationNoShow {
+		reservation.Status = status
+		return u.repo.UpdateReservationStatus(reservation)
+	}
+	if fee, err = u.cancellationFee(reservation, status == model.ReservationNoShow); err != nil {
+		return nil, err
+	}
+	reservation.Status = status
+	return u.repo.CancelReservation(reservation, fee)
+}
+
+func (u *ReservationService) CheckInReservation(in uuid.UUID) (*model.Reservation, error) {
+	var err error
+	var reservation *model.Reservation
+	if reservation, err = u.GetReservationByReservationId(in); err != nil {
+		return nil, err
+	}
+	if err = validateTransition(reservation.Status, model.ReservationCheckedIn); err != nil {
+		return nil, err
+	}
+	if today().Before(reservation.CheckIn) {
+		return nil, errors.New("error CRMS : It is too early to check in")
+	}
+	if !today().Before(reservation.CheckOut) {
+		return nil, errors.New("error CRMS : The reservation has already ended")
+	}
+
+	history := &model.History{
+		Id:              uuid.New(),
+		CustomerId:      reservation.CustomerId,
+		Kind:            model.HistoryKindStay,
+		CheckIn:         reservation.CheckIn,
+		CheckOut:        reservation.CheckOut,
+		NumberOfPeople:  reservation.NumberOfPeople,
+		Currency:        config.Currency(),
+		RoomCharge:      model.MoneyFromMajor(reservation.Price, config.Currency()).Amount,
+		Note:            reservation.Note,
+		PriceOverridden: true,
+		RoomId:          reservation.RoomId,
+		GroupId:         reservation.GroupId,
+	}
+	// A reservation booked without an agreed price is charged what the rate plans of the room type quote
+	if reservation.Price == 0 {
+		var quote *dto.StayQuote
+		if quote, err = u.ratePlanSer.QuoteStay(uuid.Nil, uuid.Nil, &reservation.Room, history.CheckIn, history.CheckOut, history.NumberOfPeople); err != nil {
+			return nil, err
+		}
+		history.RatePlanId = &quote.RatePlan.Id
+		history.RoomCharge = model.MoneyFromMajor(quote.Total, config.Currency()).Amount
+		history.PriceOverridden = false
+	}
+	if err = history.ComputeTotals(config.TaxRates()); err != nil {
+		return nil, err
+	}
+	reservation.Status = model.ReservationCheckedIn
+	return u.repo.CheckInReservation(reservation, history)
+}
+
+// CheckOutReservation completes the history of a checked-in reservation. A guest leaving before the planned
+// date shortens the stay to the nights actually spent.
+func (u *ReservationService) CheckOutReservation(in uuid.UUID) (*model.Reservation, error) {
+	var err error
+	var reservation *model.Reservation
+	if reservation, err = u.GetReservationByReservationId(in); err != nil {
+		return nil, err
+	}
+	if err = validateTransition(reservation.Status, model.ReservationCheckedOut); err != nil {
+		return nil, err
+	}
+	if reservation.HistoryId == nil {
+		return nil, errors.New("error CRMS : There is no this history")
+	}
+
+	history := &model.History{
+		Id: *reservation.HistoryId,
+	}
+	if history, err = u.repo.GetReservationHistory(history); err != nil {
+		return nil, err
+	} else if history.CustomerId == uuid.Nil {
+		return nil, errors.New("error CRMS : There is no this history")
+	}
+	if departure := today(); departure.After(reservation.CheckIn) && departure.Before(reservation.CheckOut) {
+		if err = shortenStay(reservation, history, departure); err != nil {
+			return nil, err
+		}
+	}
+	reservation.Status = model.ReservationCheckedOut
+	return u.repo.CheckOutReservation(reservation, history)
+}
+
+// shortenStay moves the check-out of a reservation and its stay to an early departure and charges the nights stayed
+// only: the room charge and the price of the reservation are cut in proportion, the discount is kept within the
+// charges left and the taxes of the stay are computed again at their rates.
+func shortenStay(reservation *model.Reservation, history *model.History, departure time.Time) error {
+	booked := int64(history.Nights())
+	history.CheckOut = departure
+	stayed := int64(history.Nights())
+	if booked > 0 && stayed < booked {
+		history.RoomCharge = int64(math.Round(float64(history.RoomCharge*stayed) / float64(booked)))
+		reservation.Price = int(math.Round(float64(int64(reservation.Price)*stayed) / float64(booked)))
+	}
+	if history.Discount > history.RoomCharge+history.ExtraCharge {
+		history.Discount = history.RoomCharge + history.ExtraCharge
+	}
+	var taxes []model.TaxRate
+	for _, line := range history.TaxLines {
+		taxes = append(taxes, model.TaxRate{Name: line.Name, Rate: line.Rate, Inclusive: line.Inclusive})
+	}
+	reservation.CheckOut = departure
+	return history.ComputeTotals(taxes)
+}
+
+// cancellationFee prices the fee of the reservation policy as a History, or returns nil when nothing is charged.
+// Reservations booked without a policy are cancelled for free.
+func (u *ReservationService) cancellationFee(reservation *model.Reservation, noShow bool) (*model.History, error) {
+	var err error
+	if reservation.CancellationPolicyId == nil {
+		return nil, nil
+	}
+	policy := &model.CancellationPolicy{
+		Id: *reservation.CancellationPolicyId,
+	}
+	if policy, err = u.repo.GetCancellationPolicy(policy); err != nil {
+		return nil, err
+	} else if policy.Name == "" {
+		return nil, nil
+	}
+	percent := policy.FeePercent(reservation.CheckIn, today(), noShow)
+	price := model.MoneyFromMajor(reservation.Price, config.Currency())
+	amount := price.Percent(percent)
+	if amount.Amount == 0 {
+		return nil, nil
+	}
+
+	kind, label := model.HistoryKindCancellationFee, "Cancellation fee"
+	if noShow {
+		kind, label = model.HistoryKindNoShowFee, "No-show fee"
+	}
+	fee := &model.History{
+		Id:              uuid.New(),
+		CustomerId:      reservation.CustomerId,
+		Kind:            kind,
+		CheckIn:         reservation.CheckIn,
+		CheckOut:        reservation.CheckOut,
+		NumberOfPeople:  reservation.NumberOfPeople,
+		Currency:        amount.Currency,
+		ExtraCharge:     amount.Amount,
+		Note:            fmt.Sprintf("%s: %s%% of %s under %s", label, strconv.FormatFloat(percent, 'f', -1, 64), price, policy.Name),
+		PriceOverridden: true,
+		RoomId:          reservation.RoomId,
+		GroupId:         reservation.GroupId,
+	}
+	if err = fee.ComputeTotals(config.TaxRates()); err != nil {
+		return nil, err
+	}
+	return fee, nil
+}
+
+// confirmCancellationPolicy checks the policy the reservation is booked under, giving it the default policy when it
+// names none. An inactive policy is only kept by a reservation already booked under it, currentPolicyId.
+func (u *ReservationService) confirmCancellationPolicy(in *model.Reservation, currentPolicyId *uuid.UUID) error {
+	var err error
+	policy := &model.CancellationPolicy{}
+	if in.CancellationPolicyId != nil {
+		policy.Id = *in.CancellationPolicyId
+	}
+	if policy, err = u.repo.GetCancellationPolicy(policy); err != nil {
+		return err
+	}
+	if policy.Name == "" {
+		if in.CancellationPolicyId != nil {
+			return errors.New("error CRMS : There is no this cancellation policy")
+		}
+		return nil
+	}
+	if !policy.Active && (currentPolicyId == nil || *currentPolicyId != policy.Id) {
+		return errors.New("error CRMS : This cancellation policy is not active")
+	}
+	in.CancellationPolicyId = &policy.Id
+	return nil
+}
+
+// confirmGroup checks the booking group the reservation belongs to, nil meaning none.
+func (u *ReservationService) confirmGroup(groupId *uuid.UUID) error {
+	var err error
+	if groupId == nil {
+		return nil
+	}
+	group := &model.BookingGroup{
+		Id: *groupId,
+	}
+	if group, err = u.repo.ConfirmGroupExistence(group); err != nil {
+		return err
+	} else if group.Name == "" {
+		return errors.New("error CRMS : There is no this booking group")
+	}
+	return nil
+}
+
+func (u *ReservationService) confirmCustomer(customerId uuid.UUID) (*model.Customer, error) {
+	var err error
+	newCustomer := &model.Customer{
+		Id: customerId,
+	}
+	if newCustomer, err = u.repo.ConfirmCustomerExistence(newCustomer); err != nil {
+		return nil, err
+	} else if newCustomer.Name == "" {
+		return nil, errors.New("error CRMS : There is no this customer")
+	}
+	return newCustomer, err
+}
+
+func (u *ReservationService) confirmRoom(in *model.Reservation) error {
+	var err error
+	room := &model.Room{
+		Id: in.RoomId,
+	}
+	if room, err = u.repo.ConfirmRoomExistence(room); err != nil {
+		return err
+	} else if room.Number == "" {
+		return errors.New("error CRMS : There is no this room")
+	}
+	if !room.Active {
+		return errors.New("error CRMS : This room is not active")
+	}
+	if in.NumberOfPeople > room.Capacity {
+		return errors.New("error CRMS : Number of people exceeds room capacity")
+	}
+	return nil
+}
+
+func convertToSliceOfReservation(reservations []*model.Reservation) []*model.Reservation {
+	var reservationsSlice []*model.Reservation
+	for _, reservation := range reservations {
+		reservationsSlice = append(reservationsSlice, reservation)
+	}
+	return reservationsSlice
+}
+
+func validateTransition(from string, to string) error {
+	for _, status := range transitions[from] {
+		if status == to {
+			return nil
+		}
+	}
+	return errors.New("error CRMS : Reservation cannot change from " + from + " to " + to)
+}
+
+func validateReservationInfo(reservation *model.Reservation) error {
+	if reservation.CustomerId == uuid.Nil || reservation.RoomId == uuid.Nil {
+		return errors.New("error CRMS : Reservation Info is incomplete")
+	}
+	if reservation.CheckIn.IsZero() || reservation.CheckOut.IsZero() {
+		return errors.New("error CRMS : Reservation Info is incomplete")
+	}
+	if reservation.NumberOfPeople <= 0 {
+		return errors.New("error CRMS : Reservation Info is incomplete")
+	}
+	if reservation.Nights() <= 0 {
+		return errors.New("error CRMS : Check-out date must be after check-in date")
+	}
+	return nil
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type RetentionHandler struct {
+	ser domain.RetentionService
+}
+
+func NewRetentionHandler(e *gin.Engine, ser domain.RetentionService) {
+	handler := &RetentionHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/retentionRuleList", handler.ListRetentionRules)
+		api.POST("/retentionRuleCre", handler.CreateRetentionRule)
+		api.POST("/retentionRuleMod", handler.ModifyRetentionRule)
+		api.POST("/retentionRuleDel", handler.DeleteRetentionRule)
+		api.POST("/retentionDryRun", handler.DryRun)
+		api.POST("/retentionApply", handler.ApplyRetentionRules)
+	}
+}
+
+// ListRetentionRules @Summary ListRetentionRules
+// @Description Get all RetentionRules
+// @Tags Retention
+// @Produce application/json
+// @Success 200 {object} []model.RetentionRule
+// @Failure 500 {string} string "{"Message": "Internal Error!"}"
+// @Router /retentionRuleList [post]
+func (u *RetentionHandler) ListRetentionRules(c *gin.Context) {
+	rules, err := u.ser.ListRetentionRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": "Internal Error!",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List all retention rules",
+		"rules":   rules,
+	})
+}
+
+// CreateRetentionRule @Summary CreateRetentionRule
+// @Description Create a new RetentionRule
+// @Tags Retention
+// @Accept json
+// @Produce application/json
+// @Param RetentionRule body dto.RetentionRuleRequest true "RetentionRule Information" example: {"Entity": "customer", "CitizenshipId": 0, "Years": 5, "Action": "anonymize", "Active": true}
+// @Success 200 {object} model.RetentionRule
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /retentionRuleCre [post]
+func (u *RetentionHandler) CreateRetentionRule(c *gin.Context) {
+	request := dto.RetentionRuleRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	rule, err := u.ser.CreateRetentionRule(transformToRetentionRule(request))
+	if err != nil {
+		if err.Error() == "error CRMS : Retention rule Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// ModifyRetentionRule @Summary ModifyRetentionRule
+// @Description Modify RetentionRule
+// @Tags Retention
+// @Accept json
+// @Produce application/json
+// @Param RetentionRule body dto.RetentionRuleRequest true "RetentionRule Information"
+// @Success 200 {object} model.RetentionRule
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /retentionRuleMod [post]
+func (u *RetentionHandler) ModifyRetentionRule(c *gin.Context) {
+	request := dto.RetentionRuleRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	rule, err := u.ser.UpdateRetentionRule(transformToRetentionRule(request))
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this retention rule" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Retention rule Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRetentionRule @Summary DeleteRetentionRule
+// @Description Delete RetentionRule by RuleId
+// @Tags Retention
+// @Produce application/json
+// @Param RuleId body dto.RetentionRuleIdRequest true "RetentionRule id"
+// @Success 200 {object} string "Message": "Delete success"
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /retentionRuleDel [post]
+func (u *RetentionHandler) DeleteRetentionRule(c *gin.Context) {
+	request := dto.RetentionRuleIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	err := u.ser.DeleteRetentionRule(request.RuleId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this retention rule" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "Delete success",
+	})
+}
+
+// DryRun @Summary DryRun
+// @Description Report the customers and histories the active RetentionRules would affect, without changing them
+// @Tags Retention
+// @Produce application/json
+// @Success 200 {object} dto.RetentionReport
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /retentionDryRun [post]
+func (u *RetentionHandler) DryRun(c *gin.Context) {
+	report, err := u.ser.DryRun()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ApplyRetentionRules @Summary ApplyRetentionRules
+// @Description Anonymize or purge the customers and histories expired by the active RetentionRules
+// @Tags Retention
+// @Produce application/json
+// @Success 200 {object} dto.RetentionReport
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /retentionApply [post]
+func (u *RetentionHandler) ApplyRetentionRules(c *gin.Context) {
+	report, err := u.ser.ApplyRetentionRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func transformToRetentionRule(requestData dto.RetentionRuleRequest) *model.RetentionRule {
+	return &model.RetentionRule{
+		Id:            requestData.RuleId,
+		Entity:        requestData.Entity,
+		CitizenshipId: requestData.CitizenshipId,
+		Years:         requestData.Years,
+		Action:        requestData.Action,
+		Active:        requestData.Active,
+		Note:          requestData.Note,
+	}
+}
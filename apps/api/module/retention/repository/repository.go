@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// openReservationStatuses are the reservations still to be honoured, whose customers are never purged
+var openReservationStatuses = []string{model.ReservationTentative, model.ReservationConfirmed, model.ReservationCheckedIn}
+
+type RetentionRepository struct {
+	orm *gorm.DB
+}
+
+func NewRetentionRepository(orm *gorm.DB) domain.RetentionRepository {
+	return &RetentionRepository{
+		orm: orm,
+	}
+}
+
+func (u *RetentionRepository) ListRetentionRules() ([]*model.RetentionRule, error) {
+	var rules []*model.RetentionRule
+	err := u.orm.Find(&rules).Error
+	return rules, err
+}
+
+func (u *RetentionRepository) GetRetentionRuleById(rule *model.RetentionRule) (*model.RetentionRule, error) {
+	err := u.orm.Where("Id = ?", rule.Id).Find(&rule).Error
+	return rule, err
+}
+
+func (u *RetentionRepository) CreateRetentionRule(rule *model.RetentionRule) (*model.RetentionRule, error) {
+	err := u.orm.Create(&rule).Error
+	return rule, err
+}
+
+func (u *RetentionRepository) UpdateRetentionRule(rule *model.RetentionRule) (*model.RetentionRule, error) {
+	err := u.orm.Model(rule).Where("Id = ?", rule.Id).Select("*").Updates(&rule).Error
+	return rule, err
+}
+
+func (u *RetentionRepository) DeleteRetentionRule(rule *model.RetentionRule) error {
+	err := u.orm.Where("Id = ?", rule.Id).Delete(&rule).Error
+	return err
+}
+
+func (u *RetentionRepository) ListExpiredCustomers(rule *model.RetentionRule, cutoff time.Time, excludedCitizenships []int) ([]*model.Customer, error) {
+	var customers []*model.Customer
+	// The last activity of a customer is the end of their last stay, as customer or companion, or of their last
+	// reservation; a customer with neither is judged by the date they were recorded
+	recentStays := u.orm.Model(&model.History{}).Select("Id").Where("CheckOut >= ?", cutoff)
+	query := u.orm.Where("Id NOT IN (?)", u.orm.Model(&model.History{}).Select("CustomerId").Where("Id IN (?)", recentStays)).
+		Where("Id NOT IN (?)", u.orm.Model(&model.StayGuest{}).Select("CustomerId").Where("HistoryId IN (?)", recentStays)).
+		Where("Id NOT IN (?)", u.orm.Model(&model.Reservation{}).Select("CustomerId").Where("CheckOut >= ?", cutoff)).
+		Where("CreatedAt < ? OR Id IN (?) OR Id IN (?) OR Id IN (?)", cutoff,
+			u.orm.Model(&model.History{}).Select("CustomerId"),
+			u.orm.Model(&model.StayGuest{}).Select("CustomerId"),
+			u.orm.Model(&model.Reservation{}).Select("CustomerId"))
+	if rule.CitizenshipId != 0 {
+		query = query.Where("CitizenshipId = ?", rule.CitizenshipId)
+	} else if len(excludedCitizenships) != 0 {
+		query = query.Where("CitizenshipId NOT IN ?", excludedCitizenships)
+	}
+	if rule.Action == "anonymize" {
+		query = query.Where("AnonymizedAt IS NULL")
+	} else {
+		query = query.Where("Id NOT IN (?)", u.orm.Model(&model.Invoice{}).Select("CustomerId")).
+			Where("Id NOT IN (?)", u.orm.Model(&model.History{}).Select("CustomerId").Where("Id IN (?)", u.invoicedHistories())).
+			Where("Id NOT IN (?)", u.orm.Model(&model.History{}).Select("CustomerId").Where("Id IN (?)", u.paidHistories())).
+			Where("Id NOT IN (?)", u.orm.Model(&model.Reservation{}).Select("CustomerId").Where("Id IN (?)", u.paidReservations())).
+			Where("Id NOT IN (?)", u.orm.Model(&model.Reservation{}).Select("CustomerId").Where("Status IN ?", openReservationStatuses))
+	}
+	err := query.Find(&customers).Error
+	return customers, err
+}
+
+func (u *RetentionRepository) ListExpiredHistories(rule *model.RetentionRule, cutoff time.Time, excludedCitizenships []int) ([]*model.History, error) {
+	var histories []*model.History
+	query := u.orm.Where("CheckOut < ?", cutoff)
+	if rule.CitizenshipId != 0 {
+		query = query.Where("CustomerId IN (?)", u.orm.Model(&model.Customer{}).Select("Id").Where("CitizenshipId = ?", rule.CitizenshipId))
+	} else if len(excludedCitizenships) != 0 {
+		query = query.Where("CustomerId IN (?)", u.orm.Model(&model.Customer{}).Select("Id").Where("CitizenshipId NOT IN ?", excludedCitizenships))
+	}
+	if rule.Action == "anonymize" {
+		query = query.Where("Note <> ''")
+	} else {
+		query = query.Where("Id NOT IN (?)", u.invoicedHistories()).Where("Id NOT IN (?)", u.paidHistories())
+	}
+	err := query.Find(&histories).Error
+	return histories, err
+}
+
+func (u *RetentionRepository) PurgeCustomers(customerIds []uuid.UUID, logs []*model.AuditLog) error {
+	if len(customerIds) == 0 {
+		return nil
+	}
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		stays := tx.Model(&model.History{}).Select("Id").Where("CustomerId IN ?", customerIds)
+		if err := tx.Where("HistoryId IN (?)", stays).Delete(&model.HistoryTaxLine{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?) OR CustomerId IN ?", stays, customerIds).Delete(&model.StayGuest{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?)", stays).Delete(&model.StaySegment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?) OR CustomerId IN ?", stays, customerIds).Delete(&model.RegistrationSubmission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN (?) OR CustomerId IN ?", stays, customerIds).Delete(&model.Reservation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("CustomerId IN ?", customerIds).Delete(&model.History{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("Id IN ?", customerIds).Delete(&model.Customer{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(logs).Error
+	})
+}
+
+func (u *RetentionRepository) PurgeHistories(historyIds []uuid.UUID, logs []*model.AuditLog) error {
+	if len(historyIds) == 0 {
+		return nil
+	}
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("HistoryId IN ?", historyIds).Delete(&model.HistoryTaxLine{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN ?", historyIds).Delete(&model.StayGuest{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN ?", historyIds).Delete(&model.StaySegment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN ?", historyIds).Delete(&model.RegistrationSubmission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("HistoryId IN ?", historyIds).Delete(&model.Reservation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("Id IN ?", historyIds).Delete(&model.History{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(logs).Error
+	})
+}
+
+func (u *RetentionRepository) AnonymizeHistories(historyIds []uuid.UUID, logs []*model.AuditLog) error {
+	if len(historyIds) == 0 {
+		return nil
+	}
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.History{}).Where("Id IN ?", historyIds).Update("Note", "").Error; err != nil {
+			return err
+		}
+		return tx.Create(logs).Error
+	})
+}
+
+// invoicedHistories selects the Histories invoiced on their own or with their booking group. Issued invoices are
+// kept for accounting and their numbering has no gaps, so these Histories are never purged.
+func (u *RetentionRepository) invoicedHistories() *gorm.DB {
+	return u.orm.Model(&model.History{}).Select("Id").Where("Id IN (?) OR GroupId IN (?)",
+		u.orm.Model(&model.Invoice{}).Select("HistoryId").Where("HistoryId IS NOT NULL"),
+		u.orm.Model(&model.Invoice{}).Select("GroupId").Where("GroupId IS NOT NULL"))
+}
+
+// paidHistories selects the Histories with payments, entered on them or on the reservation they came from. The
+// payments ledger is a financial record like issued invoices, so these Histories are never purged either.
+func (u *RetentionRepository) paidHistories() *gorm.DB {
+	return u.orm.Model(&model.History{}).Select("Id").Where("Id IN (?) OR Id IN (?)",
+		u.orm.Model(&model.Payment{}).Select("HistoryId").Where("HistoryId IS NOT NULL"),
+		u.orm.Model(&model.Reservation{}).Select("HistoryId").Where("HistoryId IS NOT NULL AND Id IN (?)", u.paidReservations()))
+}
+
+// paidReservations selects the Reservations holding deposits, whose customers are never purged
+func (u *RetentionRepository) paidReservations() *gorm.DB {
+	return u.orm.Model(&model.Payment{}).Select("ReservationId").Where("ReservationId IS NOT NULL")
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+type RetentionService struct {
+	repo        domain.RetentionRepository
+	customerSer domain.CustomerService
+}
+
+func NewRetentionService(repo domain.RetentionRepository, customerSer domain.CustomerService) domain.RetentionService {
+	return &RetentionService{
+		repo:        repo,
+		customerSer: customerSer,
+	}
+}
+
+func (u *RetentionService) ListRetentionRules() ([]*model.RetentionRule, error) {
+	return u.repo.ListRetentionRules()
+}
+
+func (u *RetentionService) CreateRetentionRule(rule *model.RetentionRule) (*model.RetentionRule, error) {
+	if err := validateRetentionRule(rule); err != nil {
+		return nil, err
+	}
+	rule.Id = uuid.New()
+	return u.repo.CreateRetentionRule(rule)
+}
+
+func (u *RetentionService) UpdateRetentionRule(rule *model.RetentionRule) (*model.RetentionRule, error) {
+	var err error
+	if _, err = u.getRetentionRule(rule.Id); err != nil {
+		return nil, err
+	}
+	if err = validateRetentionRule(rule); err != nil {
+		return nil, err
+	}
+	return u.repo.UpdateRetentionRule(rule)
+}
+
+func (u *RetentionService) DeleteRetentionRule(ruleId uuid.UUID) error {
+	var err error
+	var rule *model.RetentionRule
+	if rule, err = u.getRetentionRule(ruleId); err != nil {
+		return err
+	}
+	return u.repo.DeleteRetentionRule(rule)
+}
+
+func (u *RetentionService) DryRun() (*dto.RetentionReport, error) {
+	return u.evaluate(true)
+}
+
+func (u *RetentionService) ApplyRetentionRules() (*dto.RetentionReport, error) {
+	return u.evaluate(false)
+}
+
+// evaluate collects the data expired by every active rule and, unless dryRun is set, anonymizes or purges it.
+// A rule bound to a citizenship takes precedence over the catch-all rule of the same entity.
+func (u *RetentionService) evaluate(dryRun bool) (*dto.RetentionReport, error) {
+	var err error
+	var rules []*model.RetentionRule
+	if rules, err = u.repo.ListRetentionRules(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &dto.RetentionReport{
+		DryRun:      dryRun,
+		GeneratedAt: now,
+		Rules:       []*dto.RetentionRuleReport{},
+	}
+	specific := map[string][]int{}
+	for _, rule := range rules {
+		if rule.Active && rule.CitizenshipId != 0 {
+			specific[rule.Entity] = append(specific[rule.Entity], rule.CitizenshipId)
+		}
+	}
+
+	for _, rule := range rules {
+		if !rule.Active {
+			continue
+		}
+		ruleReport := &dto.RetentionRuleReport{
+			Rule:        rule,
+			Cutoff:      now.AddDate(-rule.Years, 0, 0),
+			CustomerIds: []uuid.UUID{},
+			HistoryIds:  []uuid.UUID{},
+		}
+		switch rule.Entity {
+		case "customer":
+			var customers []*model.Customer
+			if customers, err = u.repo.ListExpiredCustomers(rule, ruleReport.Cutoff, specific[rule.Entity]); err != nil {
+				return nil, err
+			}
+			for _, customer := range customers {
+				ruleReport.CustomerIds = append(ruleReport.CustomerIds, customer.Id)
+			}
+		case "history":
+			var histories []*model.History
+			if histories, err = u.repo.ListExpiredHistories(rule, ruleReport.Cutoff, specific[rule.Entity]); err != nil {
+				return nil, err
+			}
+			for _, history := range histories {
+				ruleReport.HistoryIds = append(ruleReport.HistoryIds, history.Id)
+			}
+		}
+		report.Rules = append(report.Rules, ruleReport)
+
+		if dryRun {
+			continue
+		}
+		if err = u.apply(ruleReport); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func (u *RetentionService) apply(ruleReport *dto.RetentionRuleReport) error {
+	var err error
+	rule := ruleReport.Rule
+	reason := "retention rule " + rule.Id.String()
+
+	switch {
+	case rule.Entity == "customer" && rule.Action == "anonymize":
+		for _, customerId := range ruleReport.CustomerIds {
+			if err = u.customerSer.EraseCustomer(customerId, reason); err != nil {
+				return err
+			}
+		}
+		return nil
+	case rule.Entity == "customer" && rule.Action == "purge":
+		return u.repo.PurgeCustomers(ruleReport.CustomerIds, newAuditLogs("customer", rule.Action, reason, ruleReport.CustomerIds))
+	case rule.Entity == "history" && rule.Action == "anonymize":
+		return u.repo.AnonymizeHistories(ruleReport.HistoryIds, newAuditLogs("history", rule.Action, reason, ruleReport.HistoryIds))
+	case rule.Entity == "history" && rule.Action == "purge":
+		return u.repo.PurgeHistories(ruleReport.HistoryIds, newAuditLogs("history", rule.Action, reason, ruleReport.HistoryIds))
+	}
+	return nil
+}
+
+func (u *RetentionService) getRetentionRule(ruleId uuid.UUID) (*model.RetentionRule, error) {
+	var err error
+	rule := &model.RetentionRule{
+		Id: ruleId,
+	}
+	if rule, err = u.repo.GetRetentionRuleById(rule); err != nil {
+		return nil, err
+	} else if rule.Entity == "" {
+		return nil, errors.New("error CRMS : There is no this retention rule")
+	}
+	return rule, err
+}
+
+func newAuditLogs(entity string, action string, detail string, ids []uuid.UUID) []*model.AuditLog {
+	var logs []*model.AuditLog
+	for _, id := range ids {
+		logs = append(logs, &model.AuditLog{
+			Id:        uuid.New(),
+			Entity:    entity,
+			EntityId:  id.String(),
+			Action:    action,
+			Detail:    detail,
+			CreatedAt: time.Now(),
+		})
+	}
+	return logs
+}
+
+func validateRetentionRule(rule *model.RetentionRule) error {
+	if rule.Entity != "customer" && rule.Entity != "history" {
+		return errors.New("error CRMS : Retention rule Info is incomplete")
+	}
+	if rule.Action != "anonymize" && rule.Action != "purge" {
+		return errors.New("error CRMS : Retention rule Info is incomplete")
+	}
+	if rule.Years <= 0 {
+		return errors.New("error CRMS : Retention rule Info is incomplete")
+	}
+	return nil
+}
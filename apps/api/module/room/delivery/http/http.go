@@ -0,0 +1,492 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type RoomHandler struct {
+	ser domain.RoomService
+}
+
+func NewRoomHandler(e *gin.Engine, ser domain.RoomService) {
+	handler := &RoomHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/roomList", handler.ListRooms)
+		api.POST("/roomId", handler.GetRoomByRoomId)
+		api.POST("/roomNumber", handler.GetRoomByNumber)
+		api.POST("/roomCre", handler.CreateRoom)
+		api.POST("/roomMod", handler.ModifyRoom)
+		api.POST("/roomDel", handler.DeleteRoom)
+		api.POST("/roomAvailability", handler.SearchAvailableRooms)
+		api.POST("/roomOccupancy", handler.GetOccupancyCalendar)
+		api.POST("/roomOutageList", handler.ListRoomOutages)
+		api.POST("/roomOutageCre", handler.CreateRoomOutage)
+		api.POST("/roomOutageDel", handler.DeleteRoomOutage)
+	}
+}
+
+// ListRooms @Summary ListRooms
+// @Description Get all Rooms
+// @Tags Room
+// @Produce application/json
+// @Success 200 {object} []model.Room
+// @Failure 500 {string} string "{"Message": "Internal Error!"}"
+// @Router /roomList [post]
+func (u *RoomHandler) ListRooms(c *gin.Context) {
+	roomList, err := u.ser.ListRooms()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"Message": "Internal Error!",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List all rooms",
+		"rooms":   roomList,
+	})
+}
+
+// GetRoomByRoomId @Summary GetRoomByRoomId
+// @Description Get Room by RoomId
+// @Tags Room
+// @Produce application/json
+// @Param RoomId body dto.RoomIdRequest true "Room id"
+// @Success 200 {object} model.Room
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomId [post]
+func (u *RoomHandler) GetRoomByRoomId(c *gin.Context) {
+	request := dto.RoomIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	roomData, err := u.ser.GetRoomByRoomId(request.RoomId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, roomData)
+}
+
+// GetRoomByNumber @Summary GetRoomByNumber
+// @Description Get Room by room Number
+// @Tags Room
+// @Produce application/json
+// @Param Number body dto.RoomNumberRequest true "Room number" example: {"Number": "201"}
+// @Success 200 {object} model.Room
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomNumber [post]
+func (u *RoomHandler) GetRoomByNumber(c *gin.Context) {
+	request := dto.RoomNumberRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	roomData, err := u.ser.GetRoomByNumber(request.Number)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, roomData)
+}
+
+// CreateRoom @Summary CreateRoom
+// @Description Create a new Room
+// @Tags Room
+// @Accept json
+// @Produce application/json
+// @Param Room body dto.RoomRequest true "Room Information" example: {"Number": "201", "Type": "Double", "Capacity": 2, "BaseRate": 2400, "Active": true}
+// @Success 200 {object} model.Room
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomCre [post]
+func (u *RoomHandler) CreateRoom(c *gin.Context) {
+	request := dto.RoomRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	createRoom, err := u.ser.CreateRoom(transformToRoom(request))
+	if err != nil {
+		if err.Error() == "error CRMS : This room is already existed" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Room Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, createRoom)
+}
+
+// ModifyRoom @Summary ModifyRoom
+// @Description Modify Room
+// @Tags Room
+// @Accept json
+// @Produce application/json
+// @Param Room body dto.RoomRequest true "Room Information"
+// @Success 200 {object} model.Room
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomMod [post]
+func (u *RoomHandler) ModifyRoom(c *gin.Context) {
+	request := dto.RoomRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	modifyRoom, err := u.ser.UpdateRoom(transformToRoom(request))
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This room is already existed" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Room Info is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, modifyRoom)
+}
+
+// DeleteRoom @Summary DeleteRoom
+// @Description Delete Room by RoomId, rooms with histories can only be deactivated
+// @Tags Room
+// @Produce application/json
+// @Param RoomId body dto.RoomIdRequest true "Room id"
+// @Success 200 {object} string "Message": "Delete success"
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomDel [post]
+func (u *RoomHandler) DeleteRoom(c *gin.Context) {
+	request := dto.RoomIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	err := u.ser.DeleteRoom(request.RoomId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This room has histories, deactivate it instead" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This room has reservations, deactivate it instead" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "Delete success",
+	})
+}
+
+// SearchAvailableRooms @Summary SearchAvailableRooms
+// @Description Get the active Rooms holding the party that are free from CheckIn to CheckOut, each quoted with the cheapest rate plan of its type
+// @Tags Room
+// @Accept json
+// @Produce application/json
+// @Param Availability body dto.RoomAvailabilityRequest true "Stay" example: {"CheckIn": "2020-01-12", "CheckOut": "2020-01-15", "NumberOfPeople": 3}
+// @Success 200 {object} []dto.RoomAvailability
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomAvailability [post]
+func (u *RoomHandler) SearchAvailableRooms(c *gin.Context) {
+	request := dto.RoomAvailabilityRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	availability, err := u.ser.SearchAvailableRooms(request.CheckIn, request.CheckOut, request.NumberOfPeople)
+	if err != nil {
+		if err.Error() == "error CRMS : Date is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Check-out date must be after check-in date" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Number of people is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List available rooms",
+		"rooms":   availability,
+	})
+}
+
+// GetOccupancyCalendar @Summary GetOccupancyCalendar
+// @Description Get the room by night grid of the period, each night holding the occupying stay or reservation, or null
+// @Tags Room
+// @Accept json
+// @Produce application/json
+// @Param During body dto.RoomOccupancyRequest true "Period" example: {"startDate": "2020-01-01", "endDate": "2020-01-14"}
+// @Success 200 {object} dto.OccupancyCalendar
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomOccupancy [post]
+func (u *RoomHandler) GetOccupancyCalendar(c *gin.Context) {
+	request := dto.RoomOccupancyRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	calendar, err := u.ser.GetOccupancyCalendar(request.StartDate, request.EndDate)
+	if err != nil {
+		if err.Error() == "error CRMS : Date is incomplete" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Start date is after end date" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Date range is too long" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, calendar)
+}
+
+// ListRoomOutages @Summary ListRoomOutages
+// @Description Get the RoomOutages of a Room, or of every room when RoomId is empty, latest first
+// @Tags Room
+// @Accept json
+// @Produce application/json
+// @Param RoomId body dto.RoomIdRequest true "Room id"
+// @Success 200 {object} []model.RoomOutage
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomOutageList [post]
+func (u *RoomHandler) ListRoomOutages(c *gin.Context) {
+	request := dto.RoomIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	outages, err := u.ser.ListRoomOutages(request.RoomId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List room outages",
+		"outages": outages,
+	})
+}
+
+// CreateRoomOutage @Summary CreateRoomOutage
+// @Description Take a Room out of service for the nights from StartDate to EndDate. It is left out of the availability search and of the inventory of the KPI report. Nights held by a stay, an open reservation or another outage are rejected
+// @Tags Room
+// @Accept json
+// @Produce application/json
+// @Param Outage body dto.RoomOutageRequest true "Room id, nights and reason" example: {"RoomId": "00000000-0000-0000-0000-000000000000", "StartDate": "2020-01-10", "EndDate": "2020-01-12", "Reason": "Bathroom repair"}
+// @Success 200 {object} model.RoomOutage
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomOutageCre [post]
+func (u *RoomHandler) CreateRoomOutage(c *gin.Context) {
+	request := dto.RoomOutageRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	outage, err := transformToRoomOutage(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	outage, err = u.ser.CreateRoomOutage(outage)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this room" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Start date is after end date" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if strings.HasPrefix(err.Error(), "error CRMS : Room is already booked") {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, outage)
+}
+
+// DeleteRoomOutage @Summary DeleteRoomOutage
+// @Description Delete RoomOutage by OutageId, putting the room back in service
+// @Tags Room
+// @Produce application/json
+// @Param OutageId body dto.RoomOutageIdRequest true "RoomOutage id"
+// @Success 200 {object} string "Message": "Delete success"
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /roomOutageDel [post]
+func (u *RoomHandler) DeleteRoomOutage(c *gin.Context) {
+	request := dto.RoomOutageIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	err := u.ser.DeleteRoomOutage(request.OutageId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this room outage" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "Delete success",
+	})
+}
+
+func transformToRoom(requestData dto.RoomRequest) *model.Room {
+	return &model.Room{
+		Id:       requestData.RoomId,
+		Number:   requestData.Number,
+		Type:     requestData.Type,
+		Capacity: requestData.Capacity,
+		BaseRate: requestData.BaseRate,
+		Active:   requestData.Active,
+	}
+}
+
+func transformToRoomOutage(requestData dto.RoomOutageRequest) (*model.RoomOutage, error) {
+	startDate, err := time.ParseInLocation("2006-01-02", requestData.StartDate, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", requestData.EndDate, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &model.RoomOutage{
+		RoomId:    requestData.RoomId,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    requestData.Reason,
+	}, nil
+}
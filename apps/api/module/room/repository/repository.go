@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/S1nceU/CRMS/apps/api/module/internal/stay"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type RoomRepository struct {
+	orm *gorm.DB
+}
+
+func NewRoomRepository(orm *gorm.DB) domain.RoomRepository {
+	return &RoomRepository{
+		orm: orm,
+	}
+}
+
+func (u *RoomRepository) ListRooms() ([]*model.Room, error) {
+	var rooms []*model.Room
+	err := u.orm.Order("Number").Find(&rooms).Error
+	return rooms, err
+}
+
+func (u *RoomRepository) GetRoomByRoomId(room *model.Room) (*model.Room, error) {
+	err := u.orm.Where("Id = ?", room.Id).Find(&room).Error
+	return room, err
+}
+
+func (u *RoomRepository) GetRoomByNumber(room *model.Room) (*model.Room, error) {
+	err := u.orm.Where("Number = ?", room.Number).Find(&room).Error
+	return room, err
+}
+
+func (u *RoomRepository) CreateRoom(room *model.Room) (*model.Room, error) {
+	err := u.orm.Create(&room).Error
+	return room, err
+}
+
+func (u *RoomRepository) UpdateRoom(room *model.Room) (*model.Room, error) {
+	err := u.orm.Model(room).Where("Id = ?", room.Id).Select("*").Updates(&room).Error
+	return room, err
+}
+
+func (u *RoomRepository) DeleteRoom(room *model.Room) error {
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("RoomId = ?", room.Id).Delete(&model.RoomOutage{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("Id = ?", room.Id).Delete(&room).Error
+	})
+}
+
+func (u *RoomRepository) CountHistoriesByRoom(room *model.Room) (int64, error) {
+	var count int64
+	segments := u.orm.Model(&model.StaySegment{}).Select("HistoryId").Where("RoomId = ?", room.Id)
+	err := u.orm.Model(&model.History{}).Where("RoomId = ? OR Id IN (?)", room.Id, segments).Count(&count).Error
+	return count, err
+}
+
+func (u *RoomRepository) CountReservationsByRoom(room *model.Room) (int64, error) {
+	var count int64
+	err := u.orm.Model(&model.Reservation{}).Where("RoomId = ?", room.Id).Count(&count).Error
+	return count, err
+}
+
+// ListAvailableRooms returns the active rooms holding numberOfPeople that no stay, open reservation or outage
+// occupies on any night between checkIn and checkOut
+func (u *RoomRepository) ListAvailableRooms(checkIn time.Time, checkOut time.Time, numberOfPeople int) ([]*model.Room, error) {
+	var rooms []*model.Room
+	stays := u.orm.Model(&model.StaySegment{}).Select("RoomId").Where("CheckIn < ? AND CheckOut > ?", checkOut, checkIn)
+	reservations := u.orm.Model(&model.Reservation{}).Select("RoomId").
+		Where("Status IN ? AND CheckIn < ? AND CheckOut > ?", []string{model.ReservationTentative, model.ReservationConfirmed}, checkOut, checkIn)
+	outages := u.orm.Model(&model.RoomOutage{}).Select("RoomId").Where("StartDate < ? AND EndDate >= ?", checkOut, checkIn)
+	err := u.orm.Where("Active = ? AND Capacity >= ?", true, numberOfPeople).
+		Where("Id NOT IN (?)", stays).
+		Where("Id NOT IN (?)", reservations).
+		Where("Id NOT IN (?)", outages).
+		Order("Capacity").Order("BaseRate").Order("Number").
+		Find(&rooms).Error
+	return rooms, err
+}
+
+// ListOccupancy returns the stay segments and the open reservations overlapping start to end, with the guest name,
+// in a single query. A stay that moved rooms appears once per room.
+func (u *RoomRepository) ListOccupancy(start time.Time, end time.Time) ([]*dto.OccupancyStay, error) {
+	var stays []*dto.OccupancyStay
+	err := u.orm.Raw(`SELECT h.Id, 'history' AS Source, s.RoomId, h.CustomerId, c.Name AS GuestName, h.NumberOfPeople,
+		CASE WHEN h.CheckOut <= ? THEN ? ELSE ? END AS Status, s.CheckIn, s.CheckOut
+		FROM stay_segments s JOIN histories h ON h.Id = s.HistoryId JOIN customers c ON c.Id = h.CustomerId
+		WHERE s.CheckIn < ? AND s.CheckOut > ?
+		UNION ALL
+		SELECT r.Id, 'reservation' AS Source, r.RoomId, r.CustomerId, c.Name AS GuestName, r.NumberOfPeople,
+		r.Status, r.CheckIn, r.CheckOut
+		FROM reservations r JOIN customers c ON c.Id = r.CustomerId
+		WHERE r.Status IN ? AND r.CheckIn < ? AND r.CheckOut > ?
+		ORDER BY CheckIn`,
+		time.Now(), model.ReservationCheckedOut, model.ReservationCheckedIn, end, start,
+		[]string{model.ReservationTentative, model.ReservationConfirmed}, end, start).
+		Scan(&stays).Error
+	return stays, err
+}
+
+func (u *RoomRepository) ListRoomOutages(outage *model.RoomOutage) ([]*model.RoomOutage, error) {
+	var outages []*model.RoomOutage
+	query := u.orm.Preload("Room")
+	if outage.RoomId != uuid.Nil {
+		query = query.Where("RoomId = ?", outage.RoomId)
+	}
+	err := query.Order("StartDate DESC").Find(&outages).Error
+	return outages, err
+}
+
+func (u *RoomRepository) GetRoomOutageById(outage *model.RoomOutage) (*model.RoomOutage, error) {
+	err := u.orm.Preload("Room").Where("Id = ?", outage.Id).Find(&outage).Error
+	return outage, err
+}
+
+func (u *RoomRepository) CreateRoomOutage(outage *model.RoomOutage) (*model.RoomOutage, error) {
+	err := u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := stay.CheckRoomAvailability(tx, outage.RoomId, outage.StartDate, outage.EndDate.AddDate(0, 0, 1)); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(&outage).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.GetRoomOutageById(outage)
+}
+
+func (u *RoomRepository) DeleteRoomOutage(outage *model.RoomOutage) error {
+	return u.orm.Where("Id = ?", outage.Id).Delete(&model.RoomOutage{}).Error
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+// maxCalendarNights bounds the occupancy calendar so a typo in a year cannot build a huge grid
+const maxCalendarNights = 366
+
+type RoomService struct {
+	repo        domain.RoomRepository
+	ratePlanSer domain.RatePlanService
+}
+
+func NewRoomService(repo domain.RoomRepository, ratePlanSer domain.RatePlanService) domain.RoomService {
+	return &RoomService{
+		repo:        repo,
+		ratePlanSer: ratePlanSer,
+	}
+}
+
+func (u *RoomService) ListRooms() ([]*model.Room, error) {
+	var err error
+	var rooms []*model.Room
+	if rooms, err = u.repo.ListRooms(); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfRoom(rooms), err
+}
+
+func (u *RoomService) GetRoomByRoomId(roomId uuid.UUID) (*model.Room, error) {
+	var err error
+	newRoom := &model.Room{
+		Id: roomId,
+	}
+	if newRoom, err = u.repo.GetRoomByRoomId(newRoom); err != nil {
+		return nil, err
+	} else if newRoom.Number == "" {
+		return nil, errors.New("error CRMS : There is no this room")
+	}
+	return newRoom, err
+}
+
+func (u *RoomService) GetRoomByNumber(number string) (*model.Room, error) {
+	var err error
+	newRoom := &model.Room{
+		Number: model.NormalizeRoomNumber(number),
+	}
+	if newRoom, err = u.repo.GetRoomByNumber(newRoom); err != nil {
+		return nil, err
+	} else if newRoom.Id == uuid.Nil {
+		return nil, errors.New("error CRMS : There is no this room")
+	}
+	return newRoom, err
+}
+
+func (u *RoomService) CreateRoom(room *model.Room) (*model.Room, error) {
+	var err error
+	var existing *model.Room
+	room.Number = model.NormalizeRoomNumber(room.Number)
+	if err = validateRoomInfo(room); err != nil {
+		return nil, err
+	}
+	if existing, err = u.repo.GetRoomByNumber(&model.Room{Number: room.Number}); err != nil {
+		return nil, err
+	} else if existing.Id != uuid.Nil {
+		return nil, errors.New("error CRMS : This room is already existed")
+	}
+	room.Id = uuid.New()
+	return u.repo.CreateRoom(room)
+}
+
+func (u *RoomService) UpdateRoom(room *model.Room) (*model.Room, error) {
+	var err error
+	var existing *model.Room
+	if _, err = u.GetRoomByRoomId(room.Id); err != nil {
+		return nil, err
+	}
+	room.Number = model.NormalizeRoomNumber(room.Number)
+	if err = validateRoomInfo(room); err != nil {
+		return nil, err
+	}
+	if existing, err = u.repo.GetRoomByNumber(&model.Room{Number: room.Number}); err != nil {
+		return nil, err
+	} else if existing.Id != uuid.Nil && existing.Id != room.Id {
+		return nil, errors.New("error CRMS : This room is already existed")
+	}
+	return u.repo.UpdateRoom(room)
+}
+
+func (u *RoomService) DeleteRoom(roomId uuid.UUID) error {
+	var err error
+	var room *model.Room
+	var count int64
+	if room, err = u.GetRoomByRoomId(roomId); err != nil {
+		return err
+	}
+	if count, err = u.repo.CountHistoriesByRoom(room); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This room has histories, deactivate it instead")
+	}
+	if count, err = u.repo.CountReservationsByRoom(room); err != nil {
+		return err
+	} else if count != 0 {
+		return errors.New("error CRMS : This room has reservations, deactivate it instead")
+	}
+	return u.repo.DeleteRoom(room)
+}
+
+// SearchAvailableRooms quotes every free room with the cheapest rate plan of its type, as a stay booked without a
+// room charge is priced. A room whose type has no rate plan is quoted at its BaseRate every night.
+func (u *RoomService) SearchAvailableRooms(checkIn string, checkOut string, numberOfPeople int) ([]*dto.RoomAvailability, error) {
+	var err error
+	var date1 time.Time
+	var date2 time.Time
+	var rooms []*model.Room
+
+	if date1, err = time.ParseInLocation("2006-01-02", checkIn, time.Local); err != nil {
+		return nil, errors.New("error CRMS : Date is incomplete")
+	}
+	if date2, err = time.ParseInLocation("2006-01-02", checkOut, time.Local); err != nil {
+		return nil, errors.New("error CRMS : Date is incomplete")
+	}
+	if !date2.After(date1) {
+		return nil, errors.New("error CRMS : Check-out date must be after check-in date")
+	}
+	if numberOfPeople <= 0 {
+		return nil, errors.New("error CRMS : Number of people is incomplete")
+	}
+
+	if rooms, err = u.repo.ListAvailableRooms(date1, date2, numberOfPeople); err != nil {
+		return nil, err
+	}
+	nights := model.NightsBetween(date1, date2)
+	availability := make([]*dto.RoomAvailability, 0, len(rooms))
+	for _, room := range rooms {
+		var quote *dto.StayQuote
+		option := &dto.RoomAvailability{
+			Room:   room,
+			Nights: nights,
+		}
+		if quote, err = u.ratePlanSer.QuoteStay(uuid.Nil, uuid.Nil, room, date1, date2, numberOfPeople); err == nil {
+			option.RatePlan = quote.RatePlan
+			option.Rate = quote.Total / nights
+			option.Total = quote.Total
+		} else if errors.Is(err, domain.ErrNoRatePlan) {
+			option.Rate = room.BaseRate
+			option.Total = room.BaseRate * nights
+		} else {
+			return nil, err
+		}
+		availability = append(availability, option)
+	}
+	return availability, nil
+}
+
+// GetOccupancyCalendar builds a tape chart of the nights from startDate to endDate. Every active room gets a row,
+// inactive rooms only when something still occupies them in the period.
+func (u *RoomService) GetOccupancyCalendar(startDate string, endDate string) (*dto.OccupancyCalendar, error) {
+	var err error
+	var date1 time.Time
+	var date2 time.Time
+	var rooms []*model.Room
+	var stays []*dto.OccupancyStay
+
+	if date1, err = time.ParseInLocation("2006-01-02", startDate, time.Local); err != nil {
+		return nil, errors.New("error CRMS : Date is incomplete")
+	}
+	if date2, err = time.ParseInLocation("2006-01-02", endDate, time.Local); err != nil {
+		return nil, errors.New("error CRMS : Date is incomplete")
+	}
+	if date1.After(date2) {
+		return nil, errors.New("error CRMS : Start date is after end date")
+	}
+	if date2.After(date1.AddDate(0, 0, maxCalendarNights-1)) {
+		return nil, errors.New("error CRMS : Date range is too long")
+	}
+
+	var dates []time.Time
+	for date := date1; !date.After(date2); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date)
+	}
+	if rooms, err = u.repo.ListRooms(); err != nil {
+		return nil, err
+	}
+	if stays, err = u.repo.ListOccupancy(date1, date2.AddDate(0, 0, 1)); err != nil {
+		return nil, err
+	}
+
+	rows := make(map[uuid.UUID]*dto.RoomOccupancy, len(rooms))
+	for _, room := range rooms {
+		rows[room.Id] = &dto.RoomOccupancy{
+			Room:   room,
+			Nights: make([]*dto.OccupancyStay, len(dates)),
+		}
+	}
+	for _, stay := range stays {
+		row, ok := rows[stay.RoomId]
+		if !ok {
+			continue
+		}
+		for i, date := range dates {
+			if !date.Before(stay.CheckIn) && date.Before(stay.CheckOut) && row.Nights[i] == nil {
+				row.Nights[i] = stay
+			}
+		}
+	}
+
+	calendar := &dto.OccupancyCalendar{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Dates:     make([]string, len(dates)),
+		Rooms:     make([]*dto.RoomOccupancy, 0, len(rooms)),
+	}
+	for i, date := range dates {
+		calendar.Dates[i] = date.Format("2006-01-02")
+	}
+	for _, room := range rooms {
+		row := rows[room.Id]
+		if !room.Active && isEmptyRow(row) {
+			continue
+		}
+		calendar.Rooms = append(calendar.Rooms, row)
+	}
+	return calendar, nil
+}
+
+func (u *RoomService) ListRoomOutages(roomId uuid.UUID) ([]*model.RoomOutage, error) {
+	var err error
+	var outages []*model.RoomOutage
+	if roomId != uuid.Nil {
+		if _, err = u.GetRoomByRoomId(roomId); err != nil {
+			return nil, err
+		}
+	}
+	if outages, err = u.repo.ListRoomOutages(&model.RoomOutage{RoomId: roomId}); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfRoomOutage(outages), err
+}
+
+func (u *RoomService) CreateRoomOutage(outage *model.RoomOutage) (*model.RoomOutage, error) {
+	var err error
+	if _, err = u.GetRoomByRoomId(outage.RoomId); err != nil {
+		return nil, err
+	}
+	if outage.StartDate.After(outage.EndDate) {
+		return nil, errors.New("error CRMS : Start date is after end date")
+	}
+	outage.Id = uuid.New()
+	return u.repo.CreateRoomOutage(outage)
+}
+
+func (u *RoomService) DeleteRoomOutage(outageId uuid.UUID) error {
+	var err error
+	outage := &model.RoomOutage{
+		Id: outageId,
+	}
+	if outage, err = u.repo.GetRoomOutageById(outage); err != nil {
+		return err
+	} else if outage.RoomId == uuid.Nil {
+		return errors.New("error CRMS : There is no this room outage")
+	}
+	return u.repo.DeleteRoomOutage(outage)
+}
+
+func isEmptyRow(row *dto.RoomOccupancy) bool {
+	for _, stay := range row.Nights {
+		if stay != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func convertToSliceOfRoom(rooms []*model.Room) []*model.Room {
+	var roomsSlice []*model.Room
+	for _, room := range rooms {
+		roomsSlice = append(roomsSlice, room)
+	}
+	return roomsSlice
+}
+
+func convertToSliceOfRoomOutage(outages []*model.RoomOutage) []*model.RoomOutage {
+	var outagesSlice []*model.RoomOutage
+	for _, outage := range outages {
+		outagesSlice = append(outagesSlice, outage)
+	}
+	return outagesSlice
+}
+
+func validateRoomInfo(room *model.Room) error {
+	if room.Number == "" {
+		return errors.New("error CRMS : Room Info is incomplete")
+	}
+	if room.Type == "" {
+		return errors.New("error CRMS : Room Info is incomplete")
+	}
+	if room.Capacity <= 0 {
+		return errors.New("error CRMS : Room Info is incomplete")
+	}
+	if room.BaseRate < 0 {
+		return errors.New("error CRMS : Room Info is incomplete")
+	}
+	return nil
+}
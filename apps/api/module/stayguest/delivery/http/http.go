@@ -0,0 +1,244 @@
+package http
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type StayGuestHandler struct {
+	ser domain.StayGuestService
+}
+
+func NewStayGuestHandler(e *gin.Engine, ser domain.StayGuestService) {
+	handler := &StayGuestHandler{
+		ser: ser,
+	}
+	api := e.Group("/api")
+	{
+		api.POST("/stayGuestList", handler.ListStayGuests)
+		api.POST("/stayGuestCre", handler.AddStayGuest)
+		api.POST("/stayGuestDel", handler.RemoveStayGuest)
+		api.POST("/stayGuestPrimary", handler.SetPrimaryGuest)
+		api.POST("/stayGuestCompanion", handler.ListTravelCompanions)
+	}
+}
+
+// ListStayGuests @Summary ListStayGuests
+// @Description Get the guests registered in a stay, primary guest first
+// @Tags StayGuest
+// @Produce application/json
+// @Param HistoryId body dto.StayGuestHistoryIdRequest true "History id"
+// @Success 200 {object} []model.StayGuest
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /stayGuestList [post]
+func (u *StayGuestHandler) ListStayGuests(c *gin.Context) {
+	request := dto.StayGuestHistoryIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	guests, err := u.ser.ListStayGuests(request.HistoryId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List guests of the stay",
+		"guests":  guests,
+	})
+}
+
+// AddStayGuest @Summary AddStayGuest
+// @Description Register a Customer as a companion in a stay, raising its number of people when needed
+// @Tags StayGuest
+// @Accept json
+// @Produce application/json
+// @Param StayGuest body dto.StayGuestRequest true "History id and Customer id" example: {"HistoryId": "00000000-0000-0000-0000-000000000000", "CustomerId": "00000000-0000-0000-0000-000000000000"}
+// @Success 200 {object} []model.StayGuest
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /stayGuestCre [post]
+func (u *StayGuestHandler) AddStayGuest(c *gin.Context) {
+	request := dto.StayGuestRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	guests, err := u.ser.AddStayGuest(request.HistoryId, request.CustomerId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : There is no this customer" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Guests can only be registered in stays" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This customer is already a guest of this stay" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : Number of people exceeds room capacity" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List guests of the stay",
+		"guests":  guests,
+	})
+}
+
+// RemoveStayGuest @Summary RemoveStayGuest
+// @Description Remove a companion from a stay
+// @Tags StayGuest
+// @Accept json
+// @Produce application/json
+// @Param StayGuest body dto.StayGuestRequest true "History id and Customer id"
+// @Success 200 {object} []model.StayGuest
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /stayGuestDel [post]
+func (u *StayGuestHandler) RemoveStayGuest(c *gin.Context) {
+	request := dto.StayGuestRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	guests, err := u.ser.RemoveStayGuest(request.HistoryId, request.CustomerId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This customer is not a guest of this stay" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : The primary guest cannot be removed, make another guest primary first" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List guests of the stay",
+		"guests":  guests,
+	})
+}
+
+// SetPrimaryGuest @Summary SetPrimaryGuest
+// @Description Make a guest the primary guest and customer of a stay
+// @Tags StayGuest
+// @Accept json
+// @Produce application/json
+// @Param StayGuest body dto.StayGuestRequest true "History id and Customer id"
+// @Success 200 {object} []model.StayGuest
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /stayGuestPrimary [post]
+func (u *StayGuestHandler) SetPrimaryGuest(c *gin.Context) {
+	request := dto.StayGuestRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	guests, err := u.ser.SetPrimaryGuest(request.HistoryId, request.CustomerId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this history" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else if err.Error() == "error CRMS : This customer is not a guest of this stay" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message": "List guests of the stay",
+		"guests":  guests,
+	})
+}
+
+// ListTravelCompanions @Summary ListTravelCompanions
+// @Description Get the Customers who shared a stay with a Customer, most frequent first
+// @Tags StayGuest
+// @Produce application/json
+// @Param CustomerId body dto.StayGuestCustomerIdRequest true "Customer id"
+// @Success 200 {object} []dto.TravelCompanion
+// @Failure 500 {string} string "{"Message": err.Error()}"
+// @Router /stayGuestCompanion [post]
+func (u *StayGuestHandler) ListTravelCompanions(c *gin.Context) {
+	request := dto.StayGuestCustomerIdRequest{}
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"Message": err.Error(),
+		})
+		return
+	}
+	companions, err := u.ser.ListTravelCompanions(request.CustomerId)
+	if err != nil {
+		if err.Error() == "error CRMS : There is no this customer" {
+			c.JSON(http.StatusOK, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"Message": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"Message":    "List travel companions of the customer",
+		"companions": companions,
+	})
+}
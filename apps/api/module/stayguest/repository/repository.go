@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type StayGuestRepository struct {
+	orm *gorm.DB
+}
+
+func NewStayGuestRepository(orm *gorm.DB) domain.StayGuestRepository {
+	return &StayGuestRepository{
+		orm: orm,
+	}
+}
+
+func (u *StayGuestRepository) ListStayGuests(guest *model.StayGuest) ([]*model.StayGuest, error) {
+	var guests []*model.StayGuest
+	err := u.orm.Preload("Customer.Citizenship").Where("HistoryId = ?", guest.HistoryId).Order("IsPrimary DESC").Find(&guests).Error
+	return guests, err
+}
+
+// CreateStayGuest adds guest to the stay under a lock of the history row, so guests added concurrently are counted
+// one after the other. seat is given the locked history with its rooms, guests and tax lines before guest is added;
+// it rejects the guest or raises the people and charges of the stay, which are saved along with guest.
+func (u *StayGuestRepository) CreateStayGuest(guest *model.StayGuest, seat func(history *model.History) error) error {
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		history := &model.History{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Room").Preload("Segments.Room").Preload("Guests").
+			Preload("TaxLines").Where("Id = ?", guest.HistoryId).First(history).Error; err != nil {
+			return err
+		}
+		people := history.NumberOfPeople
+		roomCharge := history.RoomCharge
+		if err := seat(history); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Create(guest).Error; err != nil {
+			return err
+		}
+		if history.NumberOfPeople == people && history.RoomCharge == roomCharge {
+			return nil
+		}
+		if err := tx.Model(&model.History{}).Where("Id = ?", history.Id).Updates(map[string]interface{}{
+			"NumberOfPeople": history.NumberOfPeople,
+			"RoomCharge":     history.RoomCharge,
+			"TaxAmount":      history.TaxAmount,
+			"TotalAmount":    history.TotalAmount,
+			"Price":          history.Price,
+		}).Error; err != nil {
+			return err
+		}
+		if history.RoomCharge == roomCharge {
+			return nil
+		}
+		if err := tx.Where("HistoryId = ?", history.Id).Delete(&model.HistoryTaxLine{}).Error; err != nil {
+			return err
+		}
+		if len(history.TaxLines) == 0 {
+			return nil
+		}
+		return tx.Create(history.TaxLines).Error
+	})
+}
+
+func (u *StayGuestRepository) DeleteStayGuest(guest *model.StayGuest) error {
+	return u.orm.Where("HistoryId = ? AND CustomerId = ? AND IsPrimary = ?", guest.HistoryId, guest.CustomerId, false).Delete(&model.StayGuest{}).Error
+}
+
+func (u *StayGuestRepository) SetPrimaryGuest(guest *model.StayGuest) error {
+	return u.orm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.StayGuest{}).Where("HistoryId = ?", guest.HistoryId).Update("IsPrimary", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.StayGuest{}).Where("HistoryId = ? AND CustomerId = ?", guest.HistoryId, guest.CustomerId).Update("IsPrimary", true).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.History{}).Where("Id = ?", guest.HistoryId).Update("CustomerId", guest.CustomerId).Error
+	})
+}
+
+// ListTravelCompanions counts, for every other customer, the stays shared with customer
+func (u *StayGuestRepository) ListTravelCompanions(customer *model.Customer) ([]*dto.TravelCompanion, error) {
+	var rows []struct {
+		CustomerId  uuid.UUID
+		Stays       int
+		LastCheckIn time.Time
+	}
+	err := u.orm.Table("stay_guests g").
+		Select("o.CustomerId, COUNT(DISTINCT g.HistoryId) AS Stays, MAX(h.CheckIn) AS LastCheckIn").
+		Joins("JOIN stay_guests o ON o.HistoryId = g.HistoryId AND o.CustomerId <> g.CustomerId").
+		Joins("JOIN histories h ON h.Id = g.HistoryId").
+		Where("g.CustomerId = ?", customer.Id).
+		Group("o.CustomerId").
+		Order("Stays DESC, LastCheckIn DESC").
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, row := range rows {
+		ids = append(ids, row.CustomerId)
+	}
+	var customers []*model.Customer
+	if err = u.orm.Preload("Citizenship").Where("Id IN ?", ids).Find(&customers).Error; err != nil {
+		return nil, err
+	}
+	index := map[uuid.UUID]*model.Customer{}
+	for _, c := range customers {
+		index[c.Id] = c
+	}
+	var companions []*dto.TravelCompanion
+	for _, row := range rows {
+		companions = append(companions, &dto.TravelCompanion{
+			Customer:    index[row.CustomerId],
+			Stays:       row.Stays,
+			LastCheckIn: row.LastCheckIn,
+		})
+	}
+	return companions, nil
+}
+
+func (u *StayGuestRepository) ConfirmHistoryExistence(history *model.History) (*model.History, error) {
+	err := u.orm.Preload("Room").Preload("Guests").Where("Id = ?", history.Id).Find(&history).Error
+	return history, err
+}
+
+func (u *StayGuestRepository) ConfirmCustomerExistence(customer *model.Customer) (*model.Customer, error) {
+	err := u.orm.Where("Id = ?", customer.Id).Find(&customer).Error
+	return customer, err
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/config"
+	"github.com/S1nceU/CRMS/apps/api/domain"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/S1nceU/CRMS/apps/api/model/dto"
+	"github.com/google/uuid"
+)
+
+type StayGuestService struct {
+	repo        domain.StayGuestRepository
+	ratePlanSer domain.RatePlanService
+}
+
+func NewStayGuestService(repo domain.StayGuestRepository, ratePlanSer domain.RatePlanService) domain.StayGuestService {
+	return &StayGuestService{
+		repo:        repo,
+		ratePlanSer: ratePlanSer,
+	}
+}
+
+func (u *StayGuestService) ListStayGuests(historyId uuid.UUID) ([]*model.StayGuest, error) {
+	var err error
+	var guests []*model.StayGuest
+	if _, err = u.confirmHistory(historyId); err != nil {
+		return nil, err
+	}
+	if guests, err = u.repo.ListStayGuests(&model.StayGuest{HistoryId: historyId}); err != nil {
+		return nil, err
+	}
+	return convertToSliceOfStayGuest(guests), err
+}
+
+// AddStayGuest registers a companion in the room. NumberOfPeople is raised when the stay has more registered guests
+// than people, as long as they fit in its rooms, and the stay is repriced for them as seatGuest describes.
+func (u *StayGuestService) AddStayGuest(historyId uuid.UUID, customerId uuid.UUID) ([]*model.StayGuest, error) {
+	var err error
+	var history *model.History
+	if history, err = u.confirmHistory(historyId); err != nil {
+		return nil, err
+	}
+	if history.Kind != model.HistoryKindStay {
+		return nil, errors.New("error CRMS : Guests can only be registered in stays")
+	}
+	if _, err = u.confirmCustomer(customerId); err != nil {
+		return nil, err
+	}
+	if findGuest(history, customerId) != nil {
+		return nil, errors.New("error CRMS : This customer is already a guest of this stay")
+	}
+	guest := &model.StayGuest{
+		Id:         uuid.New(),
+		HistoryId:  historyId,
+		CustomerId: customerId,
+	}
+	if err = u.repo.CreateStayGuest(guest, u.seatGuest); err != nil {
+		return nil, err
+	}
+	return u.ListStayGuests(historyId)
+}
+
+// RemoveStayGuest unregisters a companion. NumberOfPeople is kept, as people without a customer record, such as
+// young children, may still be in the room.
+func (u *StayGuestService) RemoveStayGuest(historyId uuid.UUID, customerId uuid.UUID) ([]*model.StayGuest, error) {
+	var err error
+	var history *model.History
+	var guest *model.StayGuest
+	if history, err = u.confirmHistory(historyId); err != nil {
+		return nil, err
+	}
+	if guest = findGuest(history, customerId); guest == nil {
+		return nil, errors.New("error CRMS : This customer is not a guest of this stay")
+	}
+	if guest.IsPrimary {
+		return nil, errors.New("error CRMS : The primary guest cannot be removed, make another guest primary first")
+	}
+	if err = u.repo.DeleteStayGuest(guest); err != nil {
+		return nil, err
+	}
+	return u.ListStayGuests(historyId)
+}
+
+// SetPrimaryGuest makes a registered guest the primary guest, which also makes it the customer of the stay
+func (u *StayGuestService) SetPrimaryGuest(historyId uuid.UUID, customerId uuid.UUID) ([]*model.StayGuest, error) {
+	var err error
+	var history *model.History
+	var guest *model.StayGuest
+	if history, err = u.confirmHistory(historyId); err != nil {
+		return nil, err
+	}
+	if guest = findGuest(history, customerId); guest == nil {
+		return nil, errors.New("error CRMS : This customer is not a guest of this stay")
+	}
+	if !guest.IsPrimary {
+		if err = u.repo.SetPrimaryGuest(guest); err != nil {
+			return nil, err
+		}
+	}
+	return u.ListStayGuests(historyId)
+}
+
+func (u *StayGuestService) ListTravelCompanions(customerId uuid.UUID) ([]*dto.TravelCompanion, error) {
+	var err error
+	var customer *model.Customer
+	if customer, err = u.confirmCustomer(customerId); err != nil {
+		return nil, err
+	}
+	return u.repo.ListTravelCompanions(customer)
+}
+
+// seatGuest checks the stay, locked with its rooms and guests, has room for one more guest in every room it spends
+// nights in, and raises its NumberOfPeople to its guests. A stay quoted by its rate plan is charged what the plan
+// asks for the extra people, so a plan pricing the room alone leaves the charges as they were; a room charge typed by
+// hand is never changed.
+func (u *StayGuestService) seatGuest(history *model.History) error {
+	var err error
+	var plan *model.RatePlan
+	var before *dto.StayQuote
+	var after *dto.StayQuote
+	guests := len(history.Guests) + 1
+	if guests > history.Room.Capacity {
+		return errors.New("error CRMS : Number of people exceeds room capacity")
+	}
+	for _, segment := range history.Segments {
+		if guests > segment.Room.Capacity {
+			return errors.New("error CRMS : Number of people exceeds room capacity")
+		}
+	}
+	if guests <= history.NumberOfPeople {
+		return nil
+	}
+	people := history.NumberOfPeople
+	history.NumberOfPeople = guests
+	if history.RatePlanId == nil || history.PriceOverridden || history.Currency != config.Currency() {
+		return nil
+	}
+
+	if plan, err = u.ratePlanSer.GetRatePlanById(*history.RatePlanId); err != nil {
+		return err
+	}
+	// The plan is quoted for its own room type, which a stay moved to another room may no longer be in
+	room := &model.Room{Id: history.RoomId, Type: plan.RoomType}
+	if before, err = u.ratePlanSer.QuoteStay(plan.Id, plan.Id, room, history.CheckIn, history.CheckOut, people); err != nil {
+		return err
+	}
+	if after, err = u.ratePlanSer.QuoteStay(plan.Id, plan.Id, room, history.CheckIn, history.CheckOut, guests); err != nil {
+		return err
+	}
+	if after.Total == before.Total {
+		return nil
+	}
+	history.RoomCharge += model.MoneyFromMajor(after.Total-before.Total, history.Currency).Amount
+	var taxes []model.TaxRate
+	for _, line := range history.TaxLines {
+		taxes = append(taxes, model.TaxRate{Name: line.Name, Rate: line.Rate, Inclusive: line.Inclusive})
+	}
+	return history.ComputeTotals(taxes)
+}
+
+func (u *StayGuestService) confirmHistory(historyId uuid.UUID) (*model.History, error) {
+	var err error
+	history := &model.History{
+		Id: historyId,
+	}
+	if history, err = u.repo.ConfirmHistoryExistence(history); err != nil {
+		return nil, err
+	} else if history.CustomerId == uuid.Nil {
+		return nil, errors.New("error CRMS : There is no this history")
+	}
+	return history, nil
+}
+
+func (u *StayGuestService) confirmCustomer(customerId uuid.UUID) (*model.Customer, error) {
+	var err error
+	customer := &model.Customer{
+		Id: customerId,
+	}
+	if customer, err = u.repo.ConfirmCustomerExistence(customer); err != nil {
+		return nil, err
+	} else if customer.Name == "" {
+		return nil, errors.New("error CRMS : There is no this customer")
+	}
+	return customer, nil
+}
+
+func findGuest(history *model.History, customerId uuid.UUID) *model.StayGuest {
+	for _, guest := range history.Guests {
+		if guest.CustomerId == customerId {
+			return guest
+		}
+	}
+	return nil
+}
+
+func convertToSliceOfStayGuest(guests []*model.StayGuest) []*model.StayGuest {
+	var guestsSlice []*model.StayGuest
+	for _, guest := range guests {
+		guestsSlice = append(guestsSlice, guest)
+	}
+	return guestsSlice
+}
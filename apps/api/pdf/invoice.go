@@ -0,0 +1,119 @@
+package pdf
+
+import (
+	"github.com/S1nceU/CRMS/apps/api/config"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"github.com/go-pdf/fpdf"
+	"io"
+)
+
+const (
+	pageMargin     = 15.0
+	lineHeight     = 6.0
+	amountWidth    = 40.0
+	contentWidth   = 210.0 - 2*pageMargin
+	fontFamily     = "Invoice"
+	fallbackFont   = "Helvetica"
+	dateLayout     = "2006-01-02"
+	dateHourLayout = "2006-01-02 15:04"
+)
+
+// WriteInvoice renders invoice as a one page A4 PDF under the header of property. Without a TrueType font in the
+// property config the core Helvetica font is used, which only prints Western European characters.
+func WriteInvoice(w io.Writer, property *config.PropertyConfig, invoice *model.Invoice) error {
+	if property == nil {
+		property = &config.PropertyConfig{}
+	}
+	doc := fpdf.New("P", "mm", "A4", "")
+	doc.SetMargins(pageMargin, pageMargin, pageMargin)
+	doc.SetAutoPageBreak(true, pageMargin)
+	family, text := fallbackFont, doc.UnicodeTranslatorFromDescriptor("")
+	if property.FontPath != "" {
+		doc.AddUTF8Font(fontFamily, "", property.FontPath)
+		doc.AddUTF8Font(fontFamily, "B", property.FontPath)
+		family, text = fontFamily, func(s string) string { return s }
+	}
+	doc.SetTitle(invoice.Number, true)
+	doc.AddPage()
+
+	// Property header
+	doc.SetFont(family, "B", 16)
+	doc.CellFormat(contentWidth, 8, text(property.Name), "", 1, "L", false, 0, "")
+	doc.SetFont(family, "", 9)
+	for _, line := range []string{property.Address, joinNonEmpty(property.Phone, property.Email), taxId(property.TaxId)} {
+		if line != "" {
+			doc.CellFormat(contentWidth, 4.5, text(line), "", 1, "L", false, 0, "")
+		}
+	}
+	doc.Ln(lineHeight)
+
+	// Invoice details
+	title := "Invoice"
+	if invoice.Kind == model.InvoiceKindCreditNote {
+		title = "Credit note"
+	}
+	doc.SetFont(family, "B", 14)
+	doc.CellFormat(contentWidth, 8, text(title+" "+invoice.Number), "", 1, "L", false, 0, "")
+	doc.SetFont(family, "", 10)
+	doc.CellFormat(contentWidth, lineHeight, text("Issued: "+invoice.IssuedAt.Format(dateHourLayout)), "", 1, "L", false, 0, "")
+	doc.CellFormat(contentWidth, lineHeight, text("Guest: "+invoice.CustomerName), "", 1, "L", false, 0, "")
+	if invoice.VoidedAt != nil {
+		doc.SetTextColor(200, 0, 0)
+		doc.CellFormat(contentWidth, lineHeight, text("VOIDED "+invoice.VoidedAt.Format(dateLayout)+": "+invoice.VoidReason), "", 1, "L", false, 0, "")
+		doc.SetTextColor(0, 0, 0)
+	}
+	doc.Ln(lineHeight)
+
+	// Lines
+	doc.SetFont(family, "B", 10)
+	doc.CellFormat(contentWidth-amountWidth, lineHeight, text("Description"), "B", 0, "L", false, 0, "")
+	doc.CellFormat(amountWidth, lineHeight, text("Amount ("+invoice.Currency+")"), "B", 1, "R", false, 0, "")
+	doc.SetFont(family, "", 10)
+	for _, line := range invoice.Lines {
+		amount := money(invoice, line.Amount)
+		if line.Included {
+			amount = "(" + amount + ")"
+		}
+		doc.CellFormat(contentWidth-amountWidth, lineHeight, text(line.Description), "", 0, "L", false, 0, "")
+		doc.CellFormat(amountWidth, lineHeight, amount, "", 1, "R", false, 0, "")
+	}
+
+	// Totals
+	doc.SetFont(family, "B", 10)
+	total(doc, text, "Total", money(invoice, invoice.TotalAmount), "T")
+	doc.SetFont(family, "", 10)
+	total(doc, text, "of which tax", money(invoice, invoice.TaxAmount), "")
+	if invoice.Kind == model.InvoiceKindInvoice {
+		total(doc, text, "Paid", money(invoice, invoice.Paid), "")
+		total(doc, text, "Balance due", money(invoice, invoice.TotalAmount-invoice.Paid), "")
+	}
+
+	if invoice.Note != "" {
+		doc.Ln(lineHeight)
+		doc.MultiCell(contentWidth, 5, text(invoice.Note), "", "L", false)
+	}
+	return doc.Output(w)
+}
+
+func total(doc *fpdf.Fpdf, text func(string) string, label string, amount string, border string) {
+	doc.CellFormat(contentWidth-amountWidth, lineHeight, text(label), border, 0, "R", false, 0, "")
+	doc.CellFormat(amountWidth, lineHeight, amount, border, 1, "R", false, 0, "")
+}
+
+func money(invoice *model.Invoice, amount int64) string {
+	return model.Money{Amount: amount, Currency: invoice.Currency}.Decimal()
+}
+
+func taxId(id string) string {
+	if id == "" {
+		return ""
+	}
+	return "Tax ID: " + id
+}
+
+func joinNonEmpty(a string, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + " / " + b
+}
@@ -0,0 +1,56 @@
+package sheet
+
+import (
+	"errors"
+)
+
+// Column describes one exportable field of T with its header in every supported language
+type Column[T any] struct {
+	Key    string
+	Labels map[string]string
+	Value  func(T) interface{}
+}
+
+// SelectColumns returns the columns named by keys in that order, or every column when keys is empty
+func SelectColumns[T any](columns []Column[T], keys []string) ([]Column[T], error) {
+	if len(keys) == 0 {
+		return columns, nil
+	}
+	index := map[string]Column[T]{}
+	for _, column := range columns {
+		index[column.Key] = column
+	}
+	var selected []Column[T]
+	for _, key := range keys {
+		column, ok := index[key]
+		if !ok {
+			return nil, errors.New("error CRMS : Unknown column " + key)
+		}
+		selected = append(selected, column)
+	}
+	return selected, nil
+}
+
+// Header returns the keys and the labels of columns in language, falling back to English
+func Header[T any](columns []Column[T], language string) ([]string, []string) {
+	keys := make([]string, len(columns))
+	labels := make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = column.Key
+		if label, ok := column.Labels[language]; ok {
+			labels[i] = label
+		} else {
+			labels[i] = column.Labels["en"]
+		}
+	}
+	return keys, labels
+}
+
+// Values returns the cells of item for columns
+func Values[T any](columns []Column[T], item T) []interface{} {
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = column.Value(item)
+	}
+	return values
+}
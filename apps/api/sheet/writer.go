@@ -0,0 +1,205 @@
+package sheet
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"strings"
+	"time"
+)
+
+// Writer writes a table row by row so large exports never have to be held in memory
+type Writer interface {
+	WriteHeader(keys []string, labels []string) error // keys name JSON Lines fields, labels are the CSV and XLSX header
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// ContentType returns the MIME type and file extension of an export format
+func ContentType(format string) (string, string, error) {
+	switch format {
+	case "csv":
+		return "text/csv; charset=utf-8", ".csv", nil
+	case "xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ".xlsx", nil
+	case "jsonl":
+		return "application/x-ndjson", ".jsonl", nil
+	case "fixed":
+		return "text/plain; charset=utf-8", ".txt", nil
+	default:
+		return "", "", errors.New("error CRMS : Unsupported file format")
+	}
+}
+
+// NewWriter returns a Writer of the given format ("csv", "xlsx" or "jsonl")
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case "csv":
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return nil, err
+		}
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case "xlsx":
+		file := excelize.NewFile()
+		stream, err := file.NewStreamWriter("Sheet1")
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxWriter{w: w, file: file, stream: stream}, nil
+	case "jsonl":
+		return &jsonlWriter{writer: bufio.NewWriter(w)}, nil
+	default:
+		return nil, errors.New("error CRMS : Unsupported file format")
+	}
+}
+
+// NewFixedWidthWriter returns a Writer of lines without header whose cells are padded with spaces or cut to widths,
+// counted in characters
+func NewFixedWidthWriter(w io.Writer, widths []int) Writer {
+	return &fixedWriter{writer: bufio.NewWriter(w), widths: widths}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (u *csvWriter) WriteHeader(keys []string, labels []string) error {
+	return u.writer.Write(labels)
+}
+
+func (u *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatValue(value)
+	}
+	return u.writer.Write(record)
+}
+
+func (u *csvWriter) Close() error {
+	u.writer.Flush()
+	return u.writer.Error()
+}
+
+type xlsxWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func (u *xlsxWriter) WriteHeader(keys []string, labels []string) error {
+	values := make([]interface{}, len(labels))
+	for i, label := range labels {
+		values[i] = label
+	}
+	return u.WriteRow(values)
+}
+
+func (u *xlsxWriter) WriteRow(values []interface{}) error {
+	u.row++
+	cell, err := excelize.CoordinatesToCellName(1, u.row)
+	if err != nil {
+		return err
+	}
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		if date, ok := value.(time.Time); ok {
+			cells[i] = formatValue(date)
+		} else {
+			cells[i] = value
+		}
+	}
+	return u.stream.SetRow(cell, cells)
+}
+
+func (u *xlsxWriter) Close() error {
+	defer u.file.Close()
+	if err := u.stream.Flush(); err != nil {
+		return err
+	}
+	return u.file.Write(u.w)
+}
+
+type jsonlWriter struct {
+	writer *bufio.Writer
+	keys   []string
+}
+
+func (u *jsonlWriter) WriteHeader(keys []string, labels []string) error {
+	u.keys = keys
+	return nil
+}
+
+// WriteRow writes one JSON object per line, keeping the order of the selected columns
+func (u *jsonlWriter) WriteRow(values []interface{}) error {
+	u.writer.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			u.writer.WriteByte(',')
+		}
+		key, _ := json.Marshal(u.keys[i])
+		if date, ok := value.(time.Time); ok {
+			value = formatValue(date)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		u.writer.Write(key)
+		u.writer.WriteByte(':')
+		u.writer.Write(data)
+	}
+	u.writer.WriteString("}\n")
+	return nil
+}
+
+func (u *jsonlWriter) Close() error {
+	return u.writer.Flush()
+}
+
+type fixedWriter struct {
+	writer *bufio.Writer
+	widths []int
+}
+
+func (u *fixedWriter) WriteHeader(keys []string, labels []string) error {
+	return nil
+}
+
+func (u *fixedWriter) WriteRow(values []interface{}) error {
+	for i, value := range values {
+		cell := []rune(formatValue(value))
+		if len(cell) > u.widths[i] {
+			cell = cell[:u.widths[i]]
+		}
+		if _, err := u.writer.WriteString(string(cell) + strings.Repeat(" ", u.widths[i]-len(cell))); err != nil {
+			return err
+		}
+	}
+	_, err := u.writer.WriteString("\n")
+	return err
+}
+
+func (u *fixedWriter) Close() error {
+	return u.writer.Flush()
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,93 @@
+package vcard
+
+import (
+	"errors"
+	"github.com/S1nceU/CRMS/apps/api/model"
+	"io"
+	"strings"
+)
+
+// maxLineOctets is the line length after which vCard content lines are folded
+const maxLineOctets = 75
+
+// ValidateVersion checks that version is a supported vCard version, defaulting to 4.0
+func ValidateVersion(version string) (string, error) {
+	switch version {
+	case "":
+		return "4.0", nil
+	case "3.0", "4.0":
+		return version, nil
+	default:
+		return "", errors.New("error CRMS : Unsupported vCard version")
+	}
+}
+
+// Write writes customer as one vCard of the given version ("3.0" or "4.0")
+func Write(w io.Writer, version string, customer *model.Customer) error {
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:" + version,
+		"UID:urn:uuid:" + customer.Id.String(),
+		"FN:" + escape(customer.Name),
+		"N:" + escape(customer.Name) + ";;;;",
+	}
+	if customer.PhoneNumber != "" {
+		if version == "3.0" {
+			lines = append(lines, "TEL;TYPE=CELL:"+escape(customer.PhoneNumber))
+		} else {
+			lines = append(lines, "TEL;VALUE=uri;TYPE=cell:tel:"+strings.ReplaceAll(customer.PhoneNumber, " ", ""))
+		}
+	}
+	if customer.Address != "" {
+		lines = append(lines, "ADR;TYPE=home:;;"+escape(customer.Address)+";;;;")
+	}
+	if !customer.Birthday.IsZero() {
+		if version == "3.0" {
+			lines = append(lines, "BDAY:"+customer.Birthday.Format("2006-01-02"))
+		} else {
+			lines = append(lines, "BDAY:"+customer.Birthday.Format("20060102"))
+		}
+	}
+	if version == "4.0" {
+		switch customer.Gender {
+		case "Male":
+			lines = append(lines, "GENDER:M")
+		case "Female":
+			lines = append(lines, "GENDER:F")
+		}
+	}
+	if customer.Citizenship.Nation != "" {
+		lines = append(lines, "NOTE:"+escape("Citizenship: "+customer.Citizenship.Nation+" ("+customer.Citizenship.Alpha3+")"))
+	}
+	lines = append(lines, "END:VCARD")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, fold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// fold splits a content line longer than 75 octets without breaking a UTF-8 character
+func fold(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+	var builder strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > maxLineOctets {
+			builder.WriteString("\r\n ")
+			width = 1
+		}
+		builder.WriteRune(r)
+		width += size
+	}
+	return builder.String()
+}
@@ -2,10 +2,11 @@ import React, { useState } from 'react';
 import { useAuth } from '../contexts/AuthContext';
 import CustomerManagement from './CustomerManagement';
 import HistoryManagement from './HistoryManagement';
+import KpiDashboard from './KpiDashboard';
 
 const Dashboard: React.FC = () => {
   const { username, logout } = useAuth();
-  const [activeTab, setActiveTab] = useState<'customers' | 'history'>('customers');
+  const [activeTab, setActiveTab] = useState<'customers' | 'history' | 'kpi'>('customers');
 
   const handleLogout = async () => {
     await logout();
@@ -57,6 +58,16 @@ const Dashboard: React.FC = () => {
             >
               History Management
             </button>
+            <button
+              onClick={() => setActiveTab('kpi')}
+              className={`py-4 px-1 border-b-2 font-medium text-sm ${
+                activeTab === 'kpi'
+                  ? 'border-indigo-500 text-indigo-600'
+                  : 'border-transparent text-gray-500 hover:text-gray-700 hover:border-gray-300'
+              }`}
+            >
+              KPIs
+            </button>
           </div>
         </div>
       </nav>
@@ -65,6 +76,7 @@ const Dashboard: React.FC = () => {
       <main className="max-w-7xl mx-auto py-6 px-4 sm:px-6 lg:px-8">
         {activeTab === 'customers' && <CustomerManagement />}
         {activeTab === 'history' && <HistoryManagement />}
+        {activeTab === 'kpi' && <KpiDashboard />}
       </main>
     </div>
   );
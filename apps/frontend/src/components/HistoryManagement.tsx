@@ -1,5 +1,5 @@
 import React, { useState, useEffect } from 'react';
-import { apiService, History, HistoryRequest, Customer } from '../services/api';
+import { apiService, History, HistoryRequest, Customer, minorUnitFactor } from '../services/api';
 
 const HistoryManagement: React.FC = () => {
   const [histories, setHistories] = useState<History[]>([]);
@@ -17,9 +17,12 @@ const HistoryManagement: React.FC = () => {
 
   const [formData, setFormData] = useState<HistoryRequest>({
     CustomerId: '',
-    Date: '',
+    CheckIn: '',
+    CheckOut: '',
     NumberOfPeople: 1,
-    Price: 0,
+    RoomCharge: 0,
+    ExtraCharge: 0,
+    Discount: 0,
     Room: '',
     Note: '',
   });
@@ -36,7 +39,7 @@ const HistoryManagement: React.FC = () => {
         const data: any = response.data as any;
         const list = Array.isArray(data) ? data : [data];
         const valid = list.filter((h: any) => h && typeof h === 'object' && 'Id' in h && 'CustomerId' in h);
-        setHistories(valid.map((h: any) => ({ ...h, Date: h.Date || '' })) as History[]);
+        setHistories(valid.map((h: any) => ({ ...h, CheckIn: h.CheckIn || '', CheckOut: h.CheckOut || '' })) as History[]);
       } else {
         setHistories([]);
       }
@@ -83,10 +86,14 @@ const HistoryManagement: React.FC = () => {
     setFormData({
       HistoryId: history.Id,
       CustomerId: history.CustomerId,
-      Date: (history.Date || '').split('T')[0],
+      CheckIn: (history.CheckIn || '').split('T')[0],
+      CheckOut: (history.CheckOut || '').split('T')[0],
       NumberOfPeople: history.NumberOfPeople,
-      Price: history.Price,
-      Room: history.Room,
+      Currency: history.Currency,
+      RoomCharge: history.RoomCharge,
+      ExtraCharge: history.ExtraCharge,
+      Discount: history.Discount,
+      Room: history.Room?.Number || '',
       Note: history.Note,
     });
     setShowForm(true);
@@ -146,7 +153,7 @@ const HistoryManagement: React.FC = () => {
         const data: any = response.data as any;
         const list = Array.isArray(data) ? data : [data];
         const valid = list.filter((h: any) => h && typeof h === 'object' && 'Id' in h && 'CustomerId' in h);
-        setHistories(valid.map((h: any) => ({ ...h, Date: h.Date || '' })) as History[]);
+        setHistories(valid.map((h: any) => ({ ...h, CheckIn: h.CheckIn || '', CheckOut: h.CheckOut || '' })) as History[]);
       } else {
         setHistories([]);
       }
@@ -162,9 +169,12 @@ const HistoryManagement: React.FC = () => {
   const resetForm = () => {
     setFormData({
       CustomerId: '',
-      Date: '',
+      CheckIn: '',
+      CheckOut: '',
       NumberOfPeople: 1,
-      Price: 0,
+      RoomCharge: 0,
+      ExtraCharge: 0,
+      Discount: 0,
       Room: '',
       Note: '',
     });
@@ -348,13 +358,26 @@ const HistoryManagement: React.FC = () => {
                 </div>
                 <div>
                   <label className="block text-sm font-medium text-gray-700 mb-1">
-                    Date *
+                    Check-in *
                   </label>
                   <input
                     type="date"
                     required
-                    value={formData.Date}
-                    onChange={(e) => setFormData({ ...formData, Date: e.target.value })}
+                    value={formData.CheckIn}
+                    onChange={(e) => setFormData({ ...formData, CheckIn: e.target.value })}
+                    className="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-indigo-500 focus:border-indigo-500"
+                  />
+                </div>
+                <div>
+                  <label className="block text-sm font-medium text-gray-700 mb-1">
+                    Check-out *
+                  </label>
+                  <input
+                    type="date"
+                    required
+                    min={formData.CheckIn}
+                    value={formData.CheckOut}
+                    onChange={(e) => setFormData({ ...formData, CheckOut: e.target.value })}
                     className="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-indigo-500 focus:border-indigo-500"
                   />
                 </div>
@@ -373,15 +396,58 @@ const HistoryManagement: React.FC = () => {
                 </div>
                 <div>
                   <label className="block text-sm font-medium text-gray-700 mb-1">
-                    Price *
+                    Room Charge (0 uses the rate plan)
+                  </label>
+                  <input
+                    type="number"
+                    required
+                    min="0"
+                    step={1 / minorUnitFactor(formData.Currency)}
+                    value={formData.RoomCharge / minorUnitFactor(formData.Currency)}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        RoomCharge: Math.round((parseFloat(e.target.value) || 0) * minorUnitFactor(formData.Currency)),
+                      })
+                    }
+                    className="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-indigo-500 focus:border-indigo-500"
+                  />
+                </div>
+                <div>
+                  <label className="block text-sm font-medium text-gray-700 mb-1">
+                    Extra Charge
+                  </label>
+                  <input
+                    type="number"
+                    required
+                    min="0"
+                    step={1 / minorUnitFactor(formData.Currency)}
+                    value={formData.ExtraCharge / minorUnitFactor(formData.Currency)}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        ExtraCharge: Math.round((parseFloat(e.target.value) || 0) * minorUnitFactor(formData.Currency)),
+                      })
+                    }
+                    className="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-indigo-500 focus:border-indigo-500"
+                  />
+                </div>
+                <div>
+                  <label className="block text-sm font-medium text-gray-700 mb-1">
+                    Discount
                   </label>
                   <input
                     type="number"
                     required
                     min="0"
-                    step="0.01"
-                    value={formData.Price}
-                    onChange={(e) => setFormData({ ...formData, Price: parseFloat(e.target.value) })}
+                    step={1 / minorUnitFactor(formData.Currency)}
+                    value={formData.Discount / minorUnitFactor(formData.Currency)}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        Discount: Math.round((parseFloat(e.target.value) || 0) * minorUnitFactor(formData.Currency)),
+                      })
+                    }
                     className="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-indigo-500 focus:border-indigo-500"
                   />
                 </div>
@@ -442,12 +508,12 @@ const HistoryManagement: React.FC = () => {
                         {getCustomerName(history.CustomerId)}
                       </p>
                       <p className="text-sm text-gray-500">
-                        {(history.Date ? history.Date.split('T')[0] : '')}
+                        {(history.CheckIn ? history.CheckIn.split('T')[0] : '')} – {(history.CheckOut ? history.CheckOut.split('T')[0] : '')}
                       </p>
                     </div>
                     <div className="flex-1">
                       <p className="text-sm text-gray-900">
-                        Room: {history.Room}
+                        Room: {history.Room?.Number}
                       </p>
                       <p className="text-sm text-gray-500">
                         People: {history.NumberOfPeople}
@@ -0,0 +1,129 @@
+import React, { useState } from 'react';
+import { apiService, KpiReport, minorUnitFactor } from '../services/api';
+
+// Amounts come in the minor unit of the report currency
+const formatAmount = (amount: number, currency: string) => {
+  const factor = minorUnitFactor(currency);
+  return (amount / factor).toLocaleString(undefined, { maximumFractionDigits: Math.round(Math.log10(factor)) });
+};
+
+const KpiDashboard: React.FC = () => {
+  const [startDate, setStartDate] = useState('');
+  const [endDate, setEndDate] = useState('');
+  const [roomType, setRoomType] = useState('');
+  const [report, setReport] = useState<KpiReport | null>(null);
+  const [error, setError] = useState<string | null>(null);
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setLoading(true);
+    setError(null);
+    try {
+      const response = await apiService.getKpiReport(startDate, endDate, roomType);
+      if (response.data) {
+        setReport(response.data);
+      } else {
+        setReport(null);
+        setError(response.Message || 'No report returned');
+      }
+    } catch (err) {
+      console.error('Failed to load KPI report:', err);
+      setError('Failed to load KPI report');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="space-y-6">
+      <form onSubmit={handleSubmit} className="bg-white shadow rounded-lg p-6 flex flex-wrap items-end gap-4">
+        <div>
+          <label className="block text-sm font-medium text-gray-700">Start Date</label>
+          <input
+            type="date"
+            value={startDate}
+            onChange={(e) => setStartDate(e.target.value)}
+            required
+            className="mt-1 block border border-gray-300 rounded-md px-3 py-2"
+          />
+        </div>
+        <div>
+          <label className="block text-sm font-medium text-gray-700">End Date</label>
+          <input
+            type="date"
+            value={endDate}
+            onChange={(e) => setEndDate(e.target.value)}
+            required
+            className="mt-1 block border border-gray-300 rounded-md px-3 py-2"
+          />
+        </div>
+        <div>
+          <label className="block text-sm font-medium text-gray-700">Room Type</label>
+          <input
+            type="text"
+            value={roomType}
+            onChange={(e) => setRoomType(e.target.value)}
+            placeholder="All types"
+            className="mt-1 block border border-gray-300 rounded-md px-3 py-2"
+          />
+        </div>
+        <button
+          type="submit"
+          disabled={loading}
+          className="bg-indigo-600 hover:bg-indigo-700 text-white px-4 py-2 rounded-md text-sm font-medium disabled:opacity-50"
+        >
+          {loading ? 'Loading...' : 'Show KPIs'}
+        </button>
+      </form>
+
+      {error && <div className="bg-red-50 text-red-700 p-4 rounded-md">{error}</div>}
+
+      {report && (
+        <>
+          <div className="grid grid-cols-1 sm:grid-cols-3 gap-4">
+            <div className="bg-white shadow rounded-lg p-6">
+              <div className="text-sm text-gray-500">Occupancy</div>
+              <div className="text-3xl font-bold text-gray-900">{report.Total.Occupancy}%</div>
+              <div className="text-sm text-gray-500">
+                {report.Total.Occupied} of {report.Total.Available} room-nights
+              </div>
+            </div>
+            <div className="bg-white shadow rounded-lg p-6">
+              <div className="text-sm text-gray-500">ADR</div>
+              <div className="text-3xl font-bold text-gray-900">
+                {formatAmount(report.Total.ADR, report.Currency)} {report.Currency}
+              </div>
+            </div>
+            <div className="bg-white shadow rounded-lg p-6">
+              <div className="text-sm text-gray-500">RevPAR</div>
+              <div className="text-3xl font-bold text-gray-900">
+                {formatAmount(report.Total.RevPAR, report.Currency)} {report.Currency}
+              </div>
+            </div>
+          </div>
+
+          <div className="bg-white shadow rounded-lg p-6">
+            <h3 className="text-lg font-medium text-gray-900 mb-4">Occupancy per night</h3>
+            <div className="flex items-end h-48 gap-px">
+              {report.Days.map((day) => (
+                <div
+                  key={day.Date}
+                  title={`${day.Date}: ${day.Occupancy}%, ADR ${formatAmount(day.ADR, report.Currency)}, RevPAR ${formatAmount(day.RevPAR, report.Currency)}`}
+                  className="flex-1 bg-indigo-500"
+                  style={{ height: `${Math.min(day.Occupancy, 100)}%` }}
+                />
+              ))}
+            </div>
+            <div className="flex justify-between text-xs text-gray-500 mt-2">
+              <span>{report.startDate}</span>
+              <span>{report.endDate}</span>
+            </div>
+          </div>
+        </>
+      )}
+    </div>
+  );
+};
+
+export default KpiDashboard;
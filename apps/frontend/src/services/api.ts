@@ -21,9 +21,13 @@ export interface CustomerRequest {
 export interface HistoryRequest {
   HistoryId?: string;
   CustomerId: string;
-  Date: string;
+  CheckIn: string;
+  CheckOut: string;
   NumberOfPeople: number;
-  Price: number;
+  Currency?: string;
+  RoomCharge: number; // Minor unit of Currency, 0 takes the price quoted by the rate plan
+  ExtraCharge: number; // Minor unit of Currency
+  Discount: number; // Minor unit of Currency
   Room: string;
   Note: string;
 }
@@ -41,13 +45,29 @@ export interface Customer {
   Note: string;
 }
 
+export interface Room {
+  Id: string;
+  Number: string;
+  Type: string;
+  Capacity: number;
+  BaseRate: number;
+  Active: boolean;
+}
+
 export interface History {
   Id: string;
   CustomerId: string;
-  Date: string;
+  CheckIn: string;
+  CheckOut: string;
   NumberOfPeople: number;
-  Price: number;
-  Room: string;
+  Price: number; // TotalAmount in the major unit
+  Currency: string;
+  RoomCharge: number;
+  ExtraCharge: number;
+  Discount: number;
+  TotalAmount: number;
+  RoomId: string;
+  Room: Room;
   Note: string;
 }
 
@@ -57,6 +77,40 @@ export interface Citizenship {
   Alpha3: string;
 }
 
+export interface KpiFigures {
+  Available: number;
+  Occupied: number;
+  RoomRevenue: number;
+  Occupancy: number;
+  ADR: number;
+  RevPAR: number;
+}
+
+export interface KpiDay extends KpiFigures {
+  Date: string;
+}
+
+export interface KpiReport {
+  startDate: string;
+  endDate: string;
+  RoomType: string;
+  Currency: string;
+  Total: KpiFigures;
+  Days: KpiDay[];
+}
+
+// Currencies whose minor unit is not 1/100, as in the API
+const zeroDecimalCurrencies = ['BIF', 'CLP', 'ISK', 'JPY', 'KRW', 'PYG', 'UGX', 'VND', 'XAF', 'XOF'];
+const threeDecimalCurrencies = ['BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND'];
+
+// minorUnitFactor returns how many minor units make one major unit of the currency
+export function minorUnitFactor(currency?: string): number {
+  const code = (currency || '').toUpperCase();
+  if (zeroDecimalCurrencies.includes(code)) return 1;
+  if (threeDecimalCurrencies.includes(code)) return 1000;
+  return 100;
+}
+
 export interface ApiResponse<T> {
   Message: string;
   data?: T;
@@ -253,6 +307,11 @@ class ApiService {
     return response;
   }
 
+  // Reports
+  async getKpiReport(startDate: string, endDate: string, roomType = ''): Promise<ApiResponse<KpiReport>> {
+    return this.makeRequest('/reportKpi', { startDate, endDate, RoomType: roomType });
+  }
+
   // Citizenship
   async getCitizenships(): Promise<ApiResponse<Citizenship[]>> {
     const response = await this.makeRequest('/citizenships');